@@ -0,0 +1,201 @@
+package archive
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"playGround/utils"
+	"strings"
+)
+
+// Format 压缩包格式
+type Format string
+
+const (
+	Zip    Format = "zip"
+	Tar    Format = "tar"
+	TarGz  Format = "tar.gz"
+	TarZst Format = "tar.zst"
+)
+
+// Limits 解压限制，所有格式共用
+type Limits = utils.ExtractLimits
+
+// Options 打包选项，所有格式共用
+type Options = utils.ZipOptions
+
+// Archiver 压缩包写入
+type Archiver interface {
+	// Add 写入一个条目，info.IsDir()为true时写入目录条目，r可为nil
+	Add(name string, info fs.FileInfo, r io.Reader) error
+	// Close 写入结尾数据，不关闭底层的 io.Writer
+	Close() error
+}
+
+// Extractor 压缩包解压
+type Extractor interface {
+	// Extract 按限制解压到dest目录，返回压缩包中第一个条目的名称
+	Extract(r io.ReaderAt, size int64, dest string, limits Limits) (string, error)
+}
+
+// NewArchiver 创建指定格式的压缩包写入，level取值同 Options.Level
+func NewArchiver(w io.Writer, format Format, level int) (Archiver, error) {
+	switch format {
+	case Zip:
+		return newZipArchiver(w, level), nil
+	case Tar, TarGz, TarZst:
+		return newTarArchiver(w, format, level)
+	}
+	return nil, unsupported(format)
+}
+
+// NewExtractor 创建指定格式的解压
+func NewExtractor(format Format) (Extractor, error) {
+	switch format {
+	case Zip:
+		return zipExtractor{}, nil
+	case Tar, TarGz, TarZst:
+		return tarExtractor{format: format}, nil
+	}
+	return nil, unsupported(format)
+}
+
+func unsupported(format Format) error {
+	return utils.NewError(utils.ErrUnsupportedType, "不支持的压缩格式", fmt.Errorf("format %q", format))
+}
+
+// Detect 根据文件头的魔数识别压缩格式
+func Detect(r io.ReaderAt) (Format, error) {
+	head := make([]byte, utils.SniffLen)
+	n, err := r.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	switch utils.DetectFileType(head[:n]) {
+	case utils.FileTypeZip:
+		return Zip, nil
+	case utils.FileTypeTar:
+		return Tar, nil
+	case utils.FileTypeGzip:
+		return TarGz, nil
+	case utils.FileTypeZstd:
+		return TarZst, nil
+	}
+	return "", utils.NewError(utils.ErrUnsupportedType, "无法识别的压缩格式", nil)
+}
+
+// Ext 返回文件名的扩展名，识别 .tar.gz 这类双扩展名
+func Ext(name string) string {
+	lower := strings.ToLower(name)
+	for _, ext := range []string{".tar.gz", ".tar.zst"} {
+		if strings.HasSuffix(lower, ext) {
+			return ext
+		}
+	}
+	return path.Ext(lower)
+}
+
+// FormatFromName 根据文件名判断压缩格式
+func FormatFromName(name string) (Format, bool) {
+	switch Ext(name) {
+	case ".zip":
+		return Zip, true
+	case ".tar":
+		return Tar, true
+	case ".tar.gz", ".tgz":
+		return TarGz, true
+	case ".tar.zst", ".tzst":
+		return TarZst, true
+	}
+	return "", false
+}
+
+// ExtractFile 自动识别格式并按限制解压，返回压缩包中第一个条目的名称
+func ExtractFile(src, dest string, limits Limits) (string, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return "", utils.NewError(utils.ErrStorage, "读取压缩文件失败", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", utils.NewError(utils.ErrStorage, "读取压缩文件失败", err)
+	}
+	return Extract(f, info.Size(), dest, limits)
+}
+
+// Extract 自动识别r的格式并按限制解压，返回压缩包中第一个条目的名称
+func Extract(r io.ReaderAt, size int64, dest string, limits Limits) (string, error) {
+	format, err := Detect(r)
+	if err != nil {
+		return "", err
+	}
+	ex, err := NewExtractor(format)
+	if err != nil {
+		return "", err
+	}
+	return ex.Extract(r, size, dest, limits)
+}
+
+// AddDir 将目录或单个文件写入压缩包，pathInArchive为其在压缩包中的路径
+func AddDir(a Archiver, dir, pathInArchive string, opt Options) error {
+	return utils.WalkFiles(dir, pathInArchive, opt, nil, func(name, fpath string, info fs.FileInfo) error {
+		if info.IsDir() {
+			return a.Add(name, info, nil)
+		}
+		f, err := os.Open(fpath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return a.Add(name, info, f)
+	})
+}
+
+// ArchiveDir 将目录按指定格式打包写入w，可用于备份
+func ArchiveDir(w io.Writer, format Format, dir, pathInArchive string, opt Options) error {
+	a, err := NewArchiver(w, format, opt.Level)
+	if err != nil {
+		return err
+	}
+	if err = AddDir(a, dir, pathInArchive, opt); err != nil {
+		a.Close()
+		return err
+	}
+	return a.Close()
+}
+
+// FindIndex 查找解压目录中层级最浅的 index.html，返回以/分隔的相对路径，找不到时返回空字符串
+func FindIndex(dir string) (string, error) {
+	index, depth := "", -1
+	err := filepath.WalkDir(dir, func(fpath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, fpath)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			// 跳过macOS打包时附带的资源目录和隐藏目录
+			if rel != "." && (d.Name() == "__MACOSX" || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := strings.ToLower(d.Name())
+		if !d.Type().IsRegular() || (name != "index.html" && name != "index.htm") {
+			return nil
+		}
+		// 同一层级按遍历顺序取第一个
+		if n := strings.Count(rel, "/"); depth < 0 || n < depth {
+			index, depth = rel, n
+		}
+		return nil
+	})
+	return index, err
+}
@@ -0,0 +1,138 @@
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"playGround/utils"
+	"testing"
+)
+
+// tarEntry 构造tar条目，typ为0时为普通文件
+type tarEntry struct {
+	name     string
+	typ      byte
+	body     string
+	linkname string
+}
+
+func buildTarGz(t *testing.T, entries ...tarEntry) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, e := range entries {
+		typ := e.typ
+		if typ == 0 {
+			typ = tar.TypeReg
+		}
+		h := &tar.Header{Name: e.name, Typeflag: typ, Mode: 0644, Size: int64(len(e.body)), Linkname: e.linkname}
+		if typ == tar.TypeDir {
+			h.Mode, h.Size = 0755, 0
+		}
+		if typ == tar.TypeSymlink || typ == tar.TypeLink {
+			h.Size = 0
+		}
+		if err := tw.WriteHeader(h); err != nil {
+			t.Fatal(err)
+		}
+		if h.Size > 0 {
+			tw.Write([]byte(e.body))
+		}
+	}
+	tw.Close()
+	gw.Close()
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestExtractTarGzRoot(t *testing.T) {
+	// tar -C dir -czf x.tgz . 生成的条目以 ./ 开头
+	r := buildTarGz(t,
+		tarEntry{name: "./", typ: tar.TypeDir},
+		tarEntry{name: "./index.html", body: "<html></html>"},
+		tarEntry{name: "./css/", typ: tar.TypeDir},
+		tarEntry{name: "./css/a.css", body: "a{}"},
+	)
+	dest := t.TempDir()
+	if _, err := Extract(r, r.Size(), dest, utils.DefaultExtractLimits); err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dest, "css", "a.css")); err != nil || string(data) != "a{}" {
+		t.Fatalf("a.css = %q, %v", data, err)
+	}
+	if index, err := FindIndex(dest); err != nil || index != "index.html" {
+		t.Fatalf("index = %q, %v", index, err)
+	}
+}
+
+func TestExtractTarGzRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry tarEntry
+	}{
+		{"zip slip", tarEntry{name: "../evil.txt", body: "x"}},
+		{"absolute", tarEntry{name: "/../evil.txt", body: "x"}},
+		{"symlink", tarEntry{name: "link", typ: tar.TypeSymlink, linkname: "/etc/passwd"}},
+		{"hardlink", tarEntry{name: "link", typ: tar.TypeLink, linkname: "/etc/passwd"}},
+		{"device", tarEntry{name: "dev", typ: tar.TypeChar}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := buildTarGz(t, tt.entry)
+			dest := t.TempDir()
+			_, err := Extract(r, r.Size(), dest, utils.DefaultExtractLimits)
+			if utils.ErrorKind(err) != utils.ErrInvalidParam {
+				t.Fatalf("err = %v, want invalid param", err)
+			}
+			if _, err := os.Stat(filepath.Join(filepath.Dir(dest), "evil.txt")); err == nil {
+				t.Fatal("file written outside dest")
+			}
+		})
+	}
+}
+
+func TestExtractTarGzBomb(t *testing.T) {
+	r := buildTarGz(t, tarEntry{name: "bomb", body: string(bytes.Repeat([]byte{0}, 8<<20))})
+	_, err := Extract(r, r.Size(), t.TempDir(), Limits{MaxRatio: 10})
+	if utils.ErrorKind(err) != utils.ErrTooLarge {
+		t.Fatalf("err = %v, want too large", err)
+	}
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	os.MkdirAll(filepath.Join(src, "sub"), 0755)
+	os.WriteFile(filepath.Join(src, "sub", "a.txt"), []byte("hello"), 0644)
+	for _, format := range []Format{Zip, Tar, TarGz, TarZst} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := ArchiveDir(&buf, format, src, "root", Options{}); err != nil {
+				t.Fatal(err)
+			}
+			r := bytes.NewReader(buf.Bytes())
+			if got, err := Detect(r); err != nil || got != format {
+				t.Fatalf("detect = %q, %v", got, err)
+			}
+			dest := t.TempDir()
+			if _, err := Extract(r, r.Size(), dest, utils.DefaultExtractLimits); err != nil {
+				t.Fatal(err)
+			}
+			if data, _ := os.ReadFile(filepath.Join(dest, "root", "sub", "a.txt")); string(data) != "hello" {
+				t.Fatalf("a.txt = %q", data)
+			}
+		})
+	}
+}
+
+func TestFormatFromName(t *testing.T) {
+	for name, want := range map[string]Format{"a.ZIP": Zip, "a.tar": Tar, "a.tar.gz": TarGz, "a.tgz": TarGz, "a.tar.zst": TarZst} {
+		if got, ok := FormatFromName(name); !ok || got != want {
+			t.Errorf("FormatFromName(%q) = %q, %v", name, got, ok)
+		}
+	}
+	if _, ok := FormatFromName("a.gz"); ok {
+		t.Error("a.gz recognized as archive")
+	}
+}
@@ -0,0 +1,191 @@
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"playGround/utils"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// zstdMaxWindow 解压zstd时允许的最大窗口，防止恶意数据占用过多内存
+const zstdMaxWindow = 64 << 20
+
+type tarArchiver struct {
+	writer     *tar.Writer
+	compressor io.WriteCloser
+}
+
+func newTarArchiver(w io.Writer, format Format, level int) (*tarArchiver, error) {
+	a := &tarArchiver{}
+	switch format {
+	case TarGz:
+		gzLevel := gzip.DefaultCompression
+		if level == utils.ZipStore {
+			gzLevel = gzip.NoCompression
+		} else if level != 0 {
+			gzLevel = level
+		}
+		gw, err := gzip.NewWriterLevel(w, gzLevel)
+		if err != nil {
+			return nil, err
+		}
+		a.compressor, w = gw, gw
+	case TarZst:
+		zstdLevel := zstd.SpeedDefault
+		if level == utils.ZipStore {
+			zstdLevel = zstd.SpeedFastest
+		} else if level != 0 {
+			zstdLevel = zstd.EncoderLevelFromZstd(level)
+		}
+		zw, err := zstd.NewWriter(w, zstd.WithEncoderLevel(zstdLevel))
+		if err != nil {
+			return nil, err
+		}
+		a.compressor, w = zw, zw
+	}
+	a.writer = tar.NewWriter(w)
+	return a, nil
+}
+
+func (a *tarArchiver) Add(name string, info fs.FileInfo, r io.Reader) error {
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	header.Uname, header.Gname = "", ""
+	if info.IsDir() {
+		header.Name += "/"
+	}
+	if err = a.writer.WriteHeader(header); err != nil {
+		return err
+	}
+	if !info.IsDir() && r != nil {
+		_, err = io.Copy(a.writer, r)
+	}
+	return err
+}
+
+func (a *tarArchiver) Close() error {
+	err := a.writer.Close()
+	if a.compressor != nil {
+		if cerr := a.compressor.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// countingReader 统计从压缩数据中读取的字节数，用于检查整体压缩比
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type tarExtractor struct {
+	format Format
+}
+
+func (e tarExtractor) Extract(r io.ReaderAt, size int64, dest string, limits Limits) (string, error) {
+	counter := &countingReader{r: io.NewSectionReader(r, 0, size)}
+	var src io.Reader = counter
+	switch e.format {
+	case TarGz:
+		gr, err := gzip.NewReader(counter)
+		if err != nil {
+			return "", utils.NewError(utils.ErrInvalidParam, "压缩文件无法解析", err)
+		}
+		defer gr.Close()
+		src = gr
+	case TarZst:
+		zr, err := zstd.NewReader(counter, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxWindow(zstdMaxWindow))
+		if err != nil {
+			return "", utils.NewError(utils.ErrInvalidParam, "压缩文件无法解析", err)
+		}
+		defer zr.Close()
+		src = zr
+	}
+
+	var first string
+	var entries int
+	guard := utils.NewExtractGuard(dest, limits)
+	tr := tar.NewReader(src)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return first, utils.NewError(utils.ErrInvalidParam, "压缩文件已损坏", err)
+		}
+		if header.Typeflag == tar.TypeXGlobalHeader {
+			continue
+		}
+		entries++
+		if first == "" {
+			first = header.Name
+		}
+		mode := header.FileInfo().Mode()
+		if header.Typeflag == tar.TypeLink {
+			// 硬链接与符号链接一样拒绝
+			mode |= fs.ModeSymlink
+		}
+		fpath, err := guard.Entry(header.Name, mode)
+		if err != nil {
+			return first, err
+		}
+		if mode.IsDir() {
+			if err = os.MkdirAll(fpath, os.ModePerm); err != nil {
+				return first, utils.NewError(utils.ErrStorage, "解压文件失败", err)
+			}
+			continue
+		}
+		if err = extractTarFile(guard, tr, fpath, mode); err != nil {
+			return first, err
+		}
+		if e.format != Tar {
+			if err = guard.CheckRatio(counter.n); err != nil {
+				return first, err
+			}
+		}
+	}
+	if entries == 0 {
+		return "", utils.NewError(utils.ErrInvalidParam, "压缩文件为空", utils.ErrEmptyArchive)
+	}
+	return first, nil
+}
+
+func extractTarFile(guard *utils.ExtractGuard, r io.Reader, fpath string, mode fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(fpath), os.ModePerm); err != nil {
+		return utils.NewError(utils.ErrStorage, "解压文件失败", err)
+	}
+	outFile, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm()|0600)
+	if err != nil {
+		return utils.NewError(utils.ErrStorage, "解压文件失败", err)
+	}
+	_, err = guard.Copy(outFile, r, 0)
+	if cerr := outFile.Close(); err == nil && cerr != nil {
+		return utils.NewError(utils.ErrStorage, "解压文件失败", cerr)
+	}
+	var e *utils.Error
+	if errors.As(err, &e) {
+		return err
+	}
+	if err != nil {
+		return utils.NewError(utils.ErrInvalidParam, "压缩文件已损坏", fmt.Errorf("%s: %w", fpath, err))
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package archive
+
+import (
+	"archive/zip"
+	"io"
+	"io/fs"
+	"playGround/utils"
+)
+
+type zipArchiver struct {
+	writer *zip.Writer
+	level  int
+}
+
+func newZipArchiver(w io.Writer, level int) *zipArchiver {
+	return &zipArchiver{writer: utils.NewZipWriter(w, level), level: level}
+}
+
+func (a *zipArchiver) Add(name string, info fs.FileInfo, r io.Reader) error {
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if info.IsDir() {
+		header.Name += "/"
+		_, err = a.writer.CreateHeader(header)
+		return err
+	}
+	header.Method = zip.Deflate
+	if a.level == utils.ZipStore {
+		header.Method = zip.Store
+	}
+	w, err := a.writer.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	if r != nil {
+		_, err = io.Copy(w, r)
+	}
+	return err
+}
+
+func (a *zipArchiver) Close() error {
+	return a.writer.Close()
+}
+
+type zipExtractor struct{}
+
+func (zipExtractor) Extract(r io.ReaderAt, size int64, dest string, limits Limits) (string, error) {
+	return utils.UnzipReader(r, size, dest, limits)
+}
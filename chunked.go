@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"playGround/archive"
+	"playGround/config"
+	"playGround/model"
+	"playGround/upload"
+	"playGround/utils"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 分片上传的默认值，配置未设置时使用
+const (
+	defaultChunkExpire   = 24 * time.Hour
+	defaultMaxChunkSize  = 32 << 20
+	defaultMaxFileSize   = 2 << 30
+	chunkCleanupInterval = 10 * time.Minute
+)
+
+// chunks 分片上传任务管理
+var chunks *upload.Manager
+
+// initChunked 创建分片上传管理并定期清理过期任务
+func initChunked(ctx context.Context) {
+	cfg := config.Conf.Upload
+	if cfg.ChunkDir == "" {
+		cfg.ChunkDir = filepath.Join(os.TempDir(), "chunked-upload")
+	}
+	if cfg.ChunkExpire <= 0 {
+		cfg.ChunkExpire = defaultChunkExpire
+	}
+	if cfg.MaxChunkSize <= 0 {
+		cfg.MaxChunkSize = defaultMaxChunkSize
+	}
+	if cfg.MaxFileSize <= 0 {
+		cfg.MaxFileSize = defaultMaxFileSize
+	}
+	chunks = upload.NewManager(cfg.ChunkDir, cfg.ChunkExpire, cfg.MaxFileSize, cfg.MaxChunkSize)
+	go chunks.Run(ctx, chunkCleanupInterval)
+}
+
+// InitiateChunked 创建分片上传任务，创建时先校验扩展名
+func InitiateChunked(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		FileName  string `json:"file_name"`
+		Name      string `json:"name"`
+		Size      int64  `json:"size"`
+		ChunkSize int64  `json:"chunk_size"`
+		Sha256    string `json:"sha256"`
+		Uploader  string `json:"uploader"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.HTTPError(w, r, utils.NewError(utils.ErrInvalidParam, "请求格式错误", err))
+		return
+	}
+	ext := archive.Ext(req.FileName)
+	allowed := false
+	for _, e := range uploadExts(req.Name) {
+		if strings.ToLower(e) == ext {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		utils.HTTPError(w, r, utils.NewError(utils.ErrUnsupportedType, "不支持的文件类型: "+ext, nil))
+		return
+	}
+	s, err := chunks.Initiate(req.FileName, req.Name, req.Uploader, req.Size, req.ChunkSize, req.Sha256)
+	if err != nil {
+		utils.HTTPError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err = JSON(w, s); err != nil {
+		utils.WithContext(r.Context()).WithError(err).Error("response err")
+	}
+}
+
+// PutChunk 上传一个分片，请求体为分片内容，X-Chunk-Sha256 头为分片的SHA-256
+func PutChunk(w http.ResponseWriter, r *http.Request) {
+	index, err := strconv.Atoi(r.PathValue("index"))
+	if err != nil {
+		utils.HTTPError(w, r, utils.NewError(utils.ErrInvalidParam, "分片序号错误", err))
+		return
+	}
+	s, err := chunks.PutChunk(r.PathValue("id"), index, r.Body, r.Header.Get("X-Chunk-Sha256"))
+	if err != nil {
+		utils.HTTPError(w, r, err)
+		return
+	}
+	if err = JSON(w, s); err != nil {
+		utils.WithContext(r.Context()).WithError(err).Error("response err")
+	}
+}
+
+// GetChunked 查询上传任务及已收到的分片，用于断点续传
+func GetChunked(w http.ResponseWriter, r *http.Request) {
+	s, err := chunks.Get(r.PathValue("id"))
+	if err != nil {
+		utils.HTTPError(w, r, err)
+		return
+	}
+	if err = JSON(w, s); err != nil {
+		utils.WithContext(r.Context()).WithError(err).Error("response err")
+	}
+}
+
+// CompleteChunked 合并分片并按普通上传的流程保存，响应与 Upload 相同
+func CompleteChunked(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	s, fpath, err := chunks.Complete(id)
+	if err != nil {
+		utils.HTTPError(w, r, err)
+		return
+	}
+	f, err := os.Open(fpath)
+	if err != nil {
+		chunks.Release(id)
+		utils.HTTPError(w, r, utils.NewError(utils.ErrStorage, "读取合并文件失败", err))
+		return
+	}
+	record := &model.Upload{Name: s.Name, FileName: s.FileName, Uploader: s.Uploader}
+	resp, err := saveUpload(r.Context(), record, f, s.Size, s.Sha256)
+	f.Close()
+	// 存储失败时保留分片，客户端可以重试合并
+	if errors.Is(err, utils.ErrStorage) {
+		chunks.Release(id)
+	} else if rerr := chunks.Remove(id); rerr != nil {
+		utils.WithContext(r.Context()).WithError(rerr).Warn("remove chunked upload err")
+	}
+	if err != nil {
+		utils.HTTPError(w, r, err)
+		return
+	}
+	if err = JSON(w, resp); err != nil {
+		utils.WithContext(r.Context()).WithError(err).Error("response err")
+	}
+}
+
+// AbortChunked 取消上传任务
+func AbortChunked(w http.ResponseWriter, r *http.Request) {
+	if err := chunks.Abort(r.PathValue("id")); err != nil {
+		utils.HTTPError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
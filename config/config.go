@@ -1,5 +1,20 @@
 package config
 
+import (
+	"log"
+	"os"
+	"playGround/health"
+	"playGround/metrics"
+	"playGround/middleware"
+	"playGround/server"
+	"playGround/storage"
+	"playGround/trace"
+	"playGround/utils"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
 var Conf = struct {
 	Port            string `yaml:"port"`
 	ProxyPort       int    `yaml:"proxy_port"`
@@ -12,6 +27,7 @@ var Conf = struct {
 	AppId           string `yaml:"app_id"`
 	AppSecret       string `yaml:"app_secret"`
 	CanUseVrMuseum  int    `yaml:"can_use_vr_museum"`
+	TrustUserHeader bool   `yaml:"trust_user_header"` //是否信任请求中的X-User作为操作人，仅在前置网关会清除客户端传入的值时开启
 	Db              struct {
 		Mongo struct {
 			Hosts    []string `yaml:"hosts"`
@@ -24,16 +40,40 @@ var Conf = struct {
 			Pwd      string `yaml:"pwd"`
 			Database int    `yaml:"database"`
 		} `yaml:"redis"`
+		Etcd struct {
+			Endpoints   []string      `yaml:"endpoints"`    //为空时不连接etcd
+			DialTimeout time.Duration `yaml:"dial_timeout"` //建立连接的超时
+		} `yaml:"etcd"`
 	} `yaml:"db"`
+	Upload struct {
+		MaxSize       int64               `yaml:"max_size"`        //单个上传请求最大字节数
+		MaxZipSize    int64               `yaml:"max_zip_size"`    //zip解压后最大字节数
+		MaxZipEntries int                 `yaml:"max_zip_entries"` //zip最多包含的文件数
+		Exts          map[string][]string `yaml:"exts"`            //各上传类别允许的扩展名
+		ChunkDir      string              `yaml:"chunk_dir"`       //分片上传的临时目录
+		ChunkExpire   time.Duration       `yaml:"chunk_expire"`    //分片上传任务无活动后的过期时间
+		MaxChunkSize  int64               `yaml:"max_chunk_size"`  //单个分片最大字节数
+		MaxFileSize   int64               `yaml:"max_file_size"`   //分片上传的文件最大字节数
+	} `yaml:"upload"`
+	Log     utils.LogConfig       `yaml:"log"`     //日志配置
+	Storage storage.Config        `yaml:"storage"` //文件存储配置
+	CORS    middleware.CORSConfig `yaml:"cors"`    //跨域配置
+	Server  server.Config         `yaml:"server"`  //HTTP服务配置
+	Health  health.Config         `yaml:"health"`  //健康检查配置
+	Metrics metrics.Config        `yaml:"metrics"` //监控指标配置
+	Trace   trace.Config          `yaml:"trace"`   //链路追踪配置
 }{}
 
-// func init() {
-// 	var confFile = "config.yaml"
-// 	yamlFile, err := os.ReadFile(confFile)
-// 	if err != nil {
-// 		log.Println("配置文件", confFile, " 不存在")
-// 		return
-// 	}
+func init() {
+	var confFile = "config.yaml"
+	yamlFile, err := os.ReadFile(confFile)
+	if err != nil {
+		log.Println("配置文件", confFile, " 不存在")
+		return
+	}
 
-// 	yaml.Unmarshal(yamlFile, &Conf)
-// }
+	// 配置格式错误时不能以零值启动
+	if err = yaml.Unmarshal(yamlFile, &Conf); err != nil {
+		log.Fatalf("解析配置文件 %s 失败: %v", confFile, err)
+	}
+}
@@ -2,55 +2,65 @@ package config
 
 import (
 	"context"
-	"fmt"
 	"log"
-	"os"
 	"time"
 
+	"github.com/go-redis/redis/v8"
+	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 var (
-	Db *mongo.Database
+	Db    *mongo.Database
+	Redis *redis.Client
+	Etcd  *clientv3.Client //未配置etcd时为nil
 )
 
-// 使用时解除注释
-// func init() {
-// 	var err error
-// 	opt := options.Client()
-// 	opt.Hosts = Conf.Db.Mongo.Hosts         //主机地址数组
-// 	opt.SetLocalThreshold(time.Second * 3). //只使用与mongo操作耗时小于3秒的
-// 						SetMaxConnIdleTime(5 * time.Millisecond). //指定连接可以保持空闲的最大毫秒数
-// 						SetMaxPoolSize(200)                       //使用最大的连接数
-
-// 	wc := writeconcern.New(writeconcern.WMajority())
-// 	readconcern.Majority()
-// 	opt.ReadConcern = readconcern.Majority()
-// 	opt.WriteConcern = wc
-// 	var client *mongo.Client
-// 	if client, err = mongo.Connect(getContext(), opt); err != nil {
-// 		checkErr(err)
-// 	}
-// 	Db = client.Database(Conf.Db.Mongo.Database)
-// }
-
-func checkErr(err error) {
-	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			fmt.Println("没有查到数据")
-			os.Exit(0)
-		} else {
-			fmt.Println(err)
-			os.Exit(0)
-		}
+func init() {
+	var err error
+	opt := options.Client()
+	opt.Hosts = Conf.Db.Mongo.Hosts         //主机地址数组
+	opt.SetLocalThreshold(time.Second * 3). //只使用与mongo操作耗时小于3秒的
+						SetMaxConnIdleTime(5 * time.Millisecond). //指定连接可以保持空闲的最大毫秒数
+						SetMaxPoolSize(200)                       //使用最大的连接数
 
+	wc := writeconcern.New(writeconcern.WMajority())
+	readconcern.Majority()
+	opt.ReadConcern = readconcern.Majority()
+	opt.WriteConcern = wc
+	if Conf.Db.Mongo.User != "" {
+		opt.SetAuth(options.Credential{Username: Conf.Db.Mongo.User, Password: Conf.Db.Mongo.Pwd})
 	}
-}
+	var client *mongo.Client
+	ctx, cancel := getContext()
+	defer cancel()
+	if client, err = mongo.Connect(ctx, opt); err != nil {
+		log.Fatalf("连接mongo失败: %v", err)
+	}
+	Db = client.Database(Conf.Db.Mongo.Database)
+
+	//redis客户端在第一次使用时才建立连接
+	Redis = redis.NewClient(&redis.Options{
+		Addr:     Conf.Db.Redis.Host,
+		Password: Conf.Db.Redis.Pwd,
+		DB:       Conf.Db.Redis.Database,
+	})
 
-func getContext() (ctx context.Context) {
-	ctx, err := context.WithTimeout(context.Background(), 10*time.Second)
-	if err != nil {
-		log.Fatal(err)
+	if len(Conf.Db.Etcd.Endpoints) > 0 {
+		dialTimeout := Conf.Db.Etcd.DialTimeout
+		if dialTimeout <= 0 {
+			dialTimeout = 5 * time.Second
+		}
+		//不阻塞等待连接，etcd不可用时由健康检查报告
+		if Etcd, err = clientv3.New(clientv3.Config{Endpoints: Conf.Db.Etcd.Endpoints, DialTimeout: dialTimeout}); err != nil {
+			log.Fatalf("连接etcd失败: %v", err)
+		}
 	}
-	return
+}
+
+func getContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), 10*time.Second)
 }
@@ -0,0 +1,212 @@
+package gateway
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"playGround/pbs"
+	"playGround/utils"
+	"playGround/validate"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gogo/protobuf/types"
+)
+
+// maxBodySize JSON请求体的最大字节数
+const maxBodySize = 1 << 20
+
+// Mux 可注册路由的对象，http.ServeMux 和 server.Server 都满足
+type Mux interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+}
+
+// RegisterArticle 将文章的REST接口映射到 ArticleServiceServer 的方法上
+func RegisterArticle(mux Mux, srv pbs.ArticleServiceServer) {
+	mux.HandleFunc("GET /articles", func(w http.ResponseWriter, r *http.Request) {
+		in := new(pbs.PageParam)
+		q := r.URL.Query()
+		if !intParam(w, r, "page", q.Get("page"), &in.Page) || !intParam(w, r, "page_size", q.Get("page_size"), &in.PageSize) {
+			return
+		}
+		for _, v := range q["types"] {
+			for _, name := range strings.Split(v, ",") {
+				t, err := pbs.ParseArticleType(name)
+				if err != nil {
+					utils.HTTPError(w, r, validate.Error([]validate.Violation{{Field: "types", Description: "文章类型不存在: " + name}}))
+					return
+				}
+				in.Types = append(in.Types, t)
+			}
+		}
+		resp, err := srv.List(r.Context(), in)
+		reply(w, r, http.StatusOK, resp, err)
+	})
+	mux.HandleFunc("POST /articles", func(w http.ResponseWriter, r *http.Request) {
+		in := new(pbs.Article)
+		if !decode(w, r, in) {
+			return
+		}
+		resp, err := srv.Create(r.Context(), in)
+		reply(w, r, http.StatusCreated, resp, err)
+	})
+	mux.HandleFunc("GET /articles/{id}", func(w http.ResponseWriter, r *http.Request) {
+		resp, err := srv.Detail(r.Context(), &pbs.ArticleId{Id: r.PathValue("id")})
+		reply(w, r, http.StatusOK, resp, err)
+	})
+	mux.HandleFunc("PUT /articles/{id}", func(w http.ResponseWriter, r *http.Request) {
+		in := new(pbs.Article)
+		if !decode(w, r, in) {
+			return
+		}
+		// 以路径中的ID为准
+		in.Id = r.PathValue("id")
+		if mask := r.URL.Query().Get("update_mask"); mask != "" {
+			in.UpdateMask = &types.FieldMask{Paths: splitPaths(mask)}
+		}
+		resp, err := srv.Update(r.Context(), in)
+		reply(w, r, http.StatusOK, resp, err)
+	})
+	// PATCH 只修改请求体中出现的字段，也可以通过update_mask参数指定
+	mux.HandleFunc("PATCH /articles/{id}", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := readBody(w, r)
+		if !ok {
+			return
+		}
+		in, fields := new(pbs.Article), map[string]json.RawMessage{}
+		if err := json.Unmarshal(body, &fields); err != nil {
+			utils.HTTPError(w, r, utils.NewError(utils.ErrInvalidParam, "请求格式错误", err))
+			return
+		}
+		if err := json.Unmarshal(body, in); err != nil {
+			utils.HTTPError(w, r, utils.NewError(utils.ErrInvalidParam, "请求格式错误", err))
+			return
+		}
+		in.Id = r.PathValue("id")
+		if mask := r.URL.Query().Get("update_mask"); mask != "" {
+			in.UpdateMask = &types.FieldMask{Paths: splitPaths(mask)}
+		} else if in.UpdateMask == nil {
+			in.UpdateMask = &types.FieldMask{}
+			for k := range fields {
+				//version用于并发控制，不是要修改的字段
+				if k != "id" && k != "update_mask" && k != "version" {
+					in.UpdateMask.Paths = append(in.UpdateMask.Paths, k)
+				}
+			}
+			sort.Strings(in.UpdateMask.Paths)
+		}
+		resp, err := srv.Update(r.Context(), in)
+		reply(w, r, http.StatusOK, resp, err)
+	})
+	mux.HandleFunc("DELETE /articles/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, err := srv.Delete(r.Context(), &pbs.ArticleId{Id: r.PathValue("id")})
+		reply(w, r, http.StatusNoContent, nil, err)
+	})
+	mux.HandleFunc("GET /articles/{id}/revisions", func(w http.ResponseWriter, r *http.Request) {
+		in := &pbs.RevisionParam{ArticleId: r.PathValue("id")}
+		q := r.URL.Query()
+		if !intParam(w, r, "page", q.Get("page"), &in.Page) || !intParam(w, r, "page_size", q.Get("page_size"), &in.PageSize) {
+			return
+		}
+		resp, err := srv.ListRevisions(r.Context(), in)
+		reply(w, r, http.StatusOK, resp, err)
+	})
+	// 比较两个版本，如 /articles/{id}/revisions/diff?from=1&to=3
+	mux.HandleFunc("GET /articles/{id}/revisions/diff", func(w http.ResponseWriter, r *http.Request) {
+		in := &pbs.DiffParam{ArticleId: r.PathValue("id")}
+		q := r.URL.Query()
+		if !intParam(w, r, "from", q.Get("from"), &in.From) || !intParam(w, r, "to", q.Get("to"), &in.To) {
+			return
+		}
+		resp, err := srv.DiffRevisions(r.Context(), in)
+		reply(w, r, http.StatusOK, resp, err)
+	})
+	mux.HandleFunc("GET /articles/{id}/revisions/{version}", func(w http.ResponseWriter, r *http.Request) {
+		in := &pbs.RevisionId{ArticleId: r.PathValue("id")}
+		if !intParam(w, r, "version", r.PathValue("version"), &in.Version) {
+			return
+		}
+		resp, err := srv.GetRevision(r.Context(), in)
+		reply(w, r, http.StatusOK, resp, err)
+	})
+	// 请求体为 {"version":要恢复的版本号,"current_version":读取到的当前版本号}
+	mux.HandleFunc("POST /articles/{id}/rollback", func(w http.ResponseWriter, r *http.Request) {
+		in := new(pbs.RollbackParam)
+		if !decode(w, r, in) {
+			return
+		}
+		in.ArticleId = r.PathValue("id")
+		resp, err := srv.Rollback(r.Context(), in)
+		reply(w, r, http.StatusCreated, resp, err)
+	})
+}
+
+// intParam 解析整数参数，为空时保持零值，格式错误时已返回错误响应
+func intParam(w http.ResponseWriter, r *http.Request, field, v string, dst *int64) bool {
+	if v == "" {
+		return true
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		utils.HTTPError(w, r, validate.Error([]validate.Violation{{Field: field, Description: "格式错误，应为整数"}}))
+		return false
+	}
+	*dst = n
+	return true
+}
+
+// decode 解析JSON请求体，失败时已返回错误响应
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	body, ok := readBody(w, r)
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		utils.HTTPError(w, r, utils.NewError(utils.ErrInvalidParam, "请求格式错误", err))
+		return false
+	}
+	return true
+}
+
+// readBody 读取请求体，超出大小限制时已返回错误响应
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		var e error = utils.NewError(utils.ErrInvalidParam, "请求格式错误", err)
+		if utils.ErrorKind(err) == utils.ErrTooLarge {
+			e = utils.NewError(utils.ErrTooLarge, "请求内容过大", err)
+		}
+		utils.HTTPError(w, r, e)
+		return nil, false
+	}
+	return body, true
+}
+
+// splitPaths 解析逗号分隔的update_mask
+func splitPaths(mask string) []string {
+	var paths []string
+	for _, p := range strings.Split(mask, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// reply 返回gRPC方法的结果，错误按gRPC状态码转换为HTTP状态码
+func reply(w http.ResponseWriter, r *http.Request, code int, resp interface{}, err error) {
+	if err != nil {
+		utils.HTTPError(w, r, err)
+		return
+	}
+	if code == http.StatusNoContent {
+		w.WriteHeader(code)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		utils.WithContext(r.Context()).WithError(err).Error("response err")
+	}
+}
@@ -0,0 +1,138 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"playGround/pbs"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeArticles 记录网关传给服务的参数
+type fakeArticles struct {
+	pbs.ArticleServiceServer
+	update *pbs.Article
+	list   *pbs.PageParam
+	err    error
+}
+
+func (f *fakeArticles) List(ctx context.Context, in *pbs.PageParam) (*pbs.Articles, error) {
+	f.list = in
+	return &pbs.Articles{}, nil
+}
+
+func (f *fakeArticles) Update(ctx context.Context, in *pbs.Article) (*pbs.Empty, error) {
+	f.update = in
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &pbs.Empty{}, nil
+}
+
+func serve(t *testing.T, srv pbs.ArticleServiceServer, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	mux := http.NewServeMux()
+	RegisterArticle(mux, srv)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return w
+}
+
+const articleId = "507f1f77bcf86cd799439011"
+
+func TestPatchMask(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		body   string
+		paths  []string
+	}{
+		{"fields in body", "/articles/" + articleId, `{"title":"t","cover_img":"","version":3,"id":"other"}`, []string{"cover_img", "title"}},
+		{"mask in query", "/articles/" + articleId + "?update_mask=content,+title", `{"title":"t","cover_img":""}`, []string{"content", "title"}},
+		{"mask in body", "/articles/" + articleId, `{"title":"t","update_mask":{"paths":["title"]}}`, []string{"title"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := new(fakeArticles)
+			if w := serve(t, f, http.MethodPatch, tt.target, tt.body); w.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", w.Code, w.Body)
+			}
+			if f.update.Id != articleId || !reflect.DeepEqual(f.update.UpdateMask.Paths, tt.paths) {
+				t.Fatalf("update id = %s, mask = %v", f.update.Id, f.update.UpdateMask.Paths)
+			}
+		})
+	}
+}
+
+func TestListTypes(t *testing.T) {
+	f := new(fakeArticles)
+	if w := serve(t, f, http.MethodGet, "/articles?types=news,ARTICLE_TYPE_NOTICE&types=2", ""); w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body)
+	}
+	want := []pbs.ArticleType{pbs.ArticleType_ARTICLE_TYPE_NEWS, pbs.ArticleType_ARTICLE_TYPE_NOTICE, pbs.ArticleType_ARTICLE_TYPE_AWARD}
+	if !reflect.DeepEqual(f.list.Types, want) {
+		t.Fatalf("types = %v", f.list.Types)
+	}
+	f.list = nil
+	if w := serve(t, f, http.MethodGet, "/articles?types=sports", ""); w.Code != http.StatusBadRequest || f.list != nil {
+		t.Fatalf("status = %d", w.Code)
+	}
+}
+
+func TestPutMask(t *testing.T) {
+	f := new(fakeArticles)
+	serve(t, f, http.MethodPut, "/articles/"+articleId, `{"title":"t","version":2}`)
+	if f.update.UpdateMask != nil || f.update.Version != 2 {
+		t.Fatalf("put without mask = %+v", f.update)
+	}
+	serve(t, f, http.MethodPut, "/articles/"+articleId+"?update_mask=title", `{"title":"t"}`)
+	if f.update.UpdateMask == nil || !reflect.DeepEqual(f.update.UpdateMask.Paths, []string{"title"}) {
+		t.Fatalf("put with mask = %+v", f.update.UpdateMask)
+	}
+}
+
+func TestPatchBadBody(t *testing.T) {
+	f := new(fakeArticles)
+	if w := serve(t, f, http.MethodPatch, "/articles/"+articleId, `[1]`); w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d", w.Code)
+	}
+	if f.update != nil {
+		t.Fatal("service called with bad body")
+	}
+}
+
+func TestUpdateConflict(t *testing.T) {
+	st, _ := status.New(codes.Aborted, "文章已被修改，请刷新后重试").WithDetails(&errdetails.ErrorInfo{
+		Reason:   "VERSION_CONFLICT",
+		Domain:   "article",
+		Metadata: map[string]string{"current_version": "5"},
+	})
+	f := &fakeArticles{err: st.Err()}
+	w := serve(t, f, http.MethodPatch, "/articles/"+articleId, `{"title":"t","version":4}`)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d", w.Code)
+	}
+	if f.update.Version != 4 {
+		t.Fatalf("version = %d", f.update.Version)
+	}
+	var resp struct {
+		Code     int               `json:"code"`
+		Error    string            `json:"error"`
+		Message  string            `json:"message"`
+		Reason   string            `json:"reason"`
+		Metadata map[string]string `json:"metadata"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Code != http.StatusConflict || resp.Reason != "VERSION_CONFLICT" || resp.Metadata["current_version"] != "5" || resp.Message != "文章已被修改，请刷新后重试" {
+		t.Fatalf("resp = %+v", resp)
+	}
+}
@@ -0,0 +1,47 @@
+package health
+
+import (
+	"context"
+	"playGround/storage"
+	"playGround/utils"
+	"strings"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// storageProbeKey 存储检查时写入后立即删除的对象
+const storageProbeKey = ".healthz"
+
+// Mongo 检查Mongo主节点是否可以访问
+func Mongo(db *mongo.Database) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		return db.Client().Ping(ctx, readpref.Primary())
+	})
+}
+
+// Redis 检查Redis是否可以访问
+func Redis(c *utils.RedisCache) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		return c.Ping(ctx)
+	})
+}
+
+// Etcd 检查etcd集群是否可以读取，线性一致读需要集群有leader
+func Etcd(c *clientv3.Client) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		_, err := c.Get(ctx, "health", clientv3.WithCountOnly())
+		return err
+	})
+}
+
+// Storage 检查文件存储是否可以写入，只查询对象时桶或目录不存在也会返回不存在，无法区分故障
+func Storage(s storage.Storage) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		if err := s.Put(ctx, storageProbeKey, strings.NewReader("ok"), 2, "text/plain"); err != nil {
+			return err
+		}
+		return s.Delete(ctx, storageProbeKey)
+	})
+}
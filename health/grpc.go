@@ -0,0 +1,92 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// grpcServer 标准的 grpc.health.v1 健康检查服务，空服务名表示整体状态，其他服务名对应注册的单项检查
+type grpcServer struct {
+	healthpb.UnimplementedHealthServer
+	h *Health
+}
+
+// GRPC 返回 grpc.health.v1 服务的实现
+func (h *Health) GRPC() healthpb.HealthServer {
+	return &grpcServer{h: h}
+}
+
+func (s *grpcServer) Check(ctx context.Context, in *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	st, ok := s.status(ctx, in.Service)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", in.Service)
+	}
+	return &healthpb.HealthCheckResponse{Status: st}, nil
+}
+
+// Watch 立即返回当前状态，之后每个缓存周期检查一次，状态变化时推送，服务退出时推送 NOT_SERVING 并结束
+func (s *grpcServer) Watch(in *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx := stream.Context()
+	ticker := time.NewTicker(s.h.ttl)
+	defer ticker.Stop()
+	last := healthpb.HealthCheckResponse_ServingStatus(-1)
+	for {
+		st, ok := s.status(ctx, in.Service)
+		if !ok {
+			st = healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+		}
+		if st != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: st}); err != nil {
+				return err
+			}
+			last = st
+		}
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-s.h.shutdown:
+			// 结束流才能让 GracefulStop 返回
+			if last != healthpb.HealthCheckResponse_NOT_SERVING {
+				stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_NOT_SERVING})
+			}
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// status 查询整体或单项的状态，服务名未注册时返回false
+func (s *grpcServer) status(ctx context.Context, service string) (healthpb.HealthCheckResponse_ServingStatus, bool) {
+	if service != "" && !s.h.registered(service) {
+		return healthpb.HealthCheckResponse_SERVICE_UNKNOWN, false
+	}
+	report := s.h.Check(ctx)
+	st := report.Status
+	if service != "" && st != StatusShutdown {
+		for _, c := range report.Checks {
+			if c.Name == service {
+				st = c.Status
+			}
+		}
+	}
+	if st == StatusUp {
+		return healthpb.HealthCheckResponse_SERVING, true
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING, true
+}
+
+// registered 是否注册了该名称的检查
+func (h *Health) registered(name string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, c := range h.checks {
+		if c.name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,218 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// 检查结果的状态
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusShutdown = "shutting_down"
+)
+
+// Config 健康检查配置，时间为0时使用默认值
+type Config struct {
+	CacheTTL time.Duration `yaml:"cache_ttl"` //检查结果的缓存时间，避免探针频繁访问依赖
+	Timeout  time.Duration `yaml:"timeout"`   //单项检查的默认超时
+}
+
+// 健康检查配置的默认值
+const (
+	defaultCacheTTL = 2 * time.Second
+	defaultTimeout  = 3 * time.Second
+)
+
+// Checker 单个依赖的检查，返回nil表示可用
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc 函数形式的 Checker
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Result 单项检查的结果
+type Result struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+	Latency string `json:"latency"`
+}
+
+// Report 所有检查的汇总，任意一项失败时整体为 down
+type Report struct {
+	Status    string    `json:"status"`
+	Checks    []Result  `json:"checks"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+type check struct {
+	name    string
+	checker Checker
+	timeout time.Duration
+}
+
+// Health 依赖检查的注册表，检查结果在 CacheTTL 内复用，并发的探针共用同一次检查
+type Health struct {
+	ttl     time.Duration
+	timeout time.Duration
+
+	mu       sync.Mutex
+	checks   []check
+	report   *Report
+	expires  time.Time
+	running  chan struct{}
+	shutdown chan struct{}
+	closed   bool
+}
+
+// New 创建健康检查
+func New(cfg Config) *Health {
+	h := &Health{ttl: cfg.CacheTTL, timeout: cfg.Timeout, shutdown: make(chan struct{})}
+	if h.ttl <= 0 {
+		h.ttl = defaultCacheTTL
+	}
+	if h.timeout <= 0 {
+		h.timeout = defaultTimeout
+	}
+	return h
+}
+
+// Register 注册依赖检查，timeout为0时使用默认超时，name同时作为gRPC健康检查的服务名
+func (h *Health) Register(name string, timeout time.Duration, c Checker) {
+	if timeout <= 0 {
+		timeout = h.timeout
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks = append(h.checks, check{name: name, checker: c, timeout: timeout})
+	h.report = nil
+}
+
+// Shutdown 标记服务正在退出，之后的就绪检查均失败，负载均衡不再转发新请求
+func (h *Health) Shutdown() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.closed {
+		h.closed = true
+		close(h.shutdown)
+	}
+}
+
+// Check 返回检查结果，缓存未过期时直接返回缓存
+func (h *Health) Check(ctx context.Context) Report {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return Report{Status: StatusShutdown, Checks: []Result{}, CheckedAt: time.Now()}
+	}
+	if h.report != nil && time.Now().Before(h.expires) {
+		r := *h.report
+		h.mu.Unlock()
+		return r
+	}
+	wait := h.running
+	if wait == nil {
+		wait = make(chan struct{})
+		h.running = wait
+		// 检查不使用请求的上下文，探针超时取消不影响其他等待者和缓存
+		go h.run(wait)
+	}
+	h.mu.Unlock()
+
+	select {
+	case <-wait:
+	case <-ctx.Done():
+		return Report{Status: StatusDown, Checks: []Result{}, CheckedAt: time.Now()}
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return *h.report
+}
+
+// run 并发执行所有检查并更新缓存
+func (h *Health) run(done chan struct{}) {
+	h.mu.Lock()
+	checks := h.checks
+	h.mu.Unlock()
+
+	r := &Report{Status: StatusUp, Checks: make([]Result, len(checks))}
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c check) {
+			defer wg.Done()
+			r.Checks[i] = c.run()
+		}(i, c)
+	}
+	wg.Wait()
+	for _, c := range r.Checks {
+		if c.Status != StatusUp {
+			r.Status = StatusDown
+		}
+	}
+	r.CheckedAt = time.Now()
+
+	h.mu.Lock()
+	h.report = r
+	h.expires = r.CheckedAt.Add(h.ttl)
+	h.running = nil
+	h.mu.Unlock()
+	close(done)
+}
+
+// run 执行单项检查，超时后不再等待忽略上下文的检查
+func (c check) run() Result {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.checker.Check(ctx)
+	}()
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	res := Result{Name: c.name, Status: StatusUp, Latency: time.Since(start).String()}
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = errors.New("timeout after " + c.timeout.String())
+		}
+		res.Status, res.Error = StatusDown, err.Error()
+	}
+	return res
+}
+
+// Liveness 存活检查，只表示进程能处理请求，不检查依赖，避免依赖故障时进程被反复重启
+func (h *Health) Liveness(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": StatusUp})
+}
+
+// Readiness 就绪检查，依赖不可用或正在退出时返回503
+func (h *Health) Readiness(w http.ResponseWriter, r *http.Request) {
+	report := h.Check(r.Context())
+	code := http.StatusOK
+	if report.Status != StatusUp {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, report)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,180 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"playGround/storage"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// counter 记录检查次数，返回err
+func counter(n *int32, err error) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		atomic.AddInt32(n, 1)
+		return err
+	})
+}
+
+func TestCheckCached(t *testing.T) {
+	h := New(Config{CacheTTL: 50 * time.Millisecond})
+	var n int32
+	h.Register("a", 0, counter(&n, nil))
+	for i := 0; i < 3; i++ {
+		if r := h.Check(context.Background()); r.Status != StatusUp || len(r.Checks) != 1 {
+			t.Fatalf("report = %+v", r)
+		}
+	}
+	if n != 1 {
+		t.Fatalf("checked %d times within ttl", n)
+	}
+	time.Sleep(60 * time.Millisecond)
+	h.Check(context.Background())
+	if n != 2 {
+		t.Fatalf("checked %d times after ttl", n)
+	}
+}
+
+func TestCheckSingleflight(t *testing.T) {
+	h := New(Config{})
+	var n int32
+	release := make(chan struct{})
+	h.Register("slow", 0, CheckerFunc(func(ctx context.Context) error {
+		atomic.AddInt32(&n, 1)
+		<-release
+		return nil
+	}))
+	var wg sync.WaitGroup
+	reports := make([]Report, 5)
+	for i := range reports {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reports[i] = h.Check(context.Background())
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if n != 1 {
+		t.Fatalf("concurrent probes checked %d times", n)
+	}
+	for _, r := range reports {
+		if r.Status != StatusUp {
+			t.Fatalf("report = %+v", r)
+		}
+	}
+}
+
+func TestCheckTimeout(t *testing.T) {
+	h := New(Config{Timeout: time.Second})
+	block := make(chan struct{})
+	defer close(block)
+	// 忽略上下文的检查超时后不再等待
+	h.Register("stuck", 20*time.Millisecond, CheckerFunc(func(ctx context.Context) error {
+		<-block
+		return nil
+	}))
+	h.Register("ok", 0, CheckerFunc(func(ctx context.Context) error { return nil }))
+	start := time.Now()
+	r := h.Check(context.Background())
+	if time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("check took %v", time.Since(start))
+	}
+	if r.Status != StatusDown || r.Checks[0].Status != StatusDown || r.Checks[0].Error != "timeout after 20ms" || r.Checks[1].Status != StatusUp {
+		t.Fatalf("report = %+v", r)
+	}
+}
+
+func TestCheckProbeCanceled(t *testing.T) {
+	h := New(Config{})
+	release := make(chan struct{})
+	h.Register("slow", 0, CheckerFunc(func(ctx context.Context) error {
+		<-release
+		return nil
+	}))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if r := h.Check(ctx); r.Status != StatusDown {
+		t.Fatalf("canceled probe = %+v", r)
+	}
+	// 探针取消不影响进行中的检查，结果照常缓存
+	close(release)
+	if r := h.Check(context.Background()); r.Status != StatusUp {
+		t.Fatalf("report = %+v", r)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	h := New(Config{})
+	var n int32
+	h.Register("a", 0, counter(&n, nil))
+	h.Check(context.Background())
+	h.Shutdown()
+	h.Shutdown()
+	if r := h.Check(context.Background()); r.Status != StatusShutdown {
+		t.Fatalf("report after shutdown = %+v", r)
+	}
+}
+
+func TestHandlers(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		shutdown bool
+		code     int
+		status   string
+	}{
+		{"up", nil, false, http.StatusOK, StatusUp},
+		{"down", errors.New("connection refused"), false, http.StatusServiceUnavailable, StatusDown},
+		{"draining", nil, true, http.StatusServiceUnavailable, StatusShutdown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(Config{})
+			var n int32
+			h.Register("a", 0, counter(&n, tt.err))
+			if tt.shutdown {
+				h.Shutdown()
+			}
+			w := httptest.NewRecorder()
+			h.Readiness(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			var r Report
+			if err := json.Unmarshal(w.Body.Bytes(), &r); err != nil {
+				t.Fatal(err)
+			}
+			if w.Code != tt.code || r.Status != tt.status || w.Header().Get("Cache-Control") != "no-store" {
+				t.Fatalf("readiness = %d %+v", w.Code, r)
+			}
+			// 存活检查不受依赖和退出状态影响
+			w = httptest.NewRecorder()
+			h.Liveness(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("liveness = %d", w.Code)
+			}
+		})
+	}
+}
+
+func TestStorageChecker(t *testing.T) {
+	root := t.TempDir()
+	if err := Storage(storage.NewLocal(root, "")).Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, storageProbeKey)); !os.IsNotExist(err) {
+		t.Fatalf("probe object left behind: %v", err)
+	}
+	// 根目录不可用时检查失败
+	file := filepath.Join(root, "file")
+	os.WriteFile(file, nil, 0644)
+	if err := Storage(storage.NewLocal(file, "")).Check(context.Background()); err == nil {
+		t.Fatal("storage with file root reported healthy")
+	}
+}
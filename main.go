@@ -2,24 +2,48 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"mime"
+	"mime/multipart"
+	"net"
 	"net/http"
 	"os"
-	"path"
 	"path/filepath"
+	"playGround/archive"
 	"playGround/config"
+	"playGround/gateway"
+	"playGround/health"
+	"playGround/metrics"
+	"playGround/middleware"
+	"playGround/model"
+	"playGround/pbs"
+	"playGround/server"
+	"playGround/service"
+	"playGround/storage"
+	"playGround/trace"
+	"playGround/upload"
 	"playGround/utils"
+	"strings"
 	"time"
 
 	clientv3 "go.etcd.io/etcd/client/v3"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func main() {
+	// go run . serve 启动HTTP服务
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		serve()
+		return
+	}
+
 	// 设置/获取环境变量
 	utils.SetEnv("MY_ENV_VAR", "my_value")
 	envValue := utils.GetEnv("MY_ENV_VAR", "default_value")
@@ -33,6 +57,17 @@ func main() {
 		configValue := utils.GetConfigValue("some_key")
 		fmt.Println("Config value:", configValue) //Config value: some_value
 	}
+	// 日志配置
+	if err := utils.InitLogger(config.Conf.Log); err != nil {
+		fmt.Println("Error init logger:", err)
+	}
+	utils.WithFields(utils.Fields{"env": envValue}).Info("logger initialized")
+	// 文件存储
+	// 存储不可用时上传、预览都无法工作，直接退出
+	if err := initStorage(context.Background()); err != nil {
+		utils.Fatalf("init storage err: %v", err)
+	}
+	initChunked(context.Background())
 	// 格式化当前时间
 	fmt.Println("Current Time =", utils.FormatTime(utils.GetCurrentTime())) //Current Time = 2024-08-30T14:38:08+08:00
 	// 时间戳转换
@@ -110,7 +145,10 @@ func main() {
 	randomStr := utils.RandomString(10)
 	fmt.Println("Random string:", randomStr) //Random string: HnCObXyiF6
 
-	// 网络请求
+	// 网络请求（设置HTTP_FIXTURE_DIR时从录制文件回放，不访问网络）
+	if fixtureDir := utils.GetEnv("HTTP_FIXTURE_DIR", ""); fixtureDir != "" {
+		utils.SetTransport(utils.NewReplayer(fixtureDir))
+	}
 	response, err := utils.HTTPGet("https://www.baidu.com")
 	if err != nil {
 		fmt.Println("Error making HTTP request:", err)
@@ -171,104 +209,545 @@ func main() {
 	}
 }
 
+// store 上传文件和生成的压缩包使用的存储
+var store storage.Storage
+
+// initStorage 按配置创建存储，本地存储未配置时沿用 UploadPath 和 UploadUrl
+func initStorage(ctx context.Context) error {
+	cfg := config.Conf.Storage
+	if cfg.Local.Root == "" {
+		cfg.Local.Root = config.Conf.UploadPath
+	}
+	if cfg.Local.BaseURL == "" {
+		cfg.Local.BaseURL = config.Conf.UploadUrl
+	}
+	s, err := storage.New(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	store = s
+	return nil
+}
+
 // 上传压缩文件
 func UploadProgram(examId string, program map[string]string, scoreStandardId string) (ncPath string, err error) {
-	// 创建一个临时目录来存放文本文件
 	nowDate := time.Now().Format("20060102")
-	filePath := config.Conf.UploadPath + nowDate + "/" + examId + "/"
-	fileUrl := config.Conf.UploadUrl + nowDate + "/" + examId + "/"
-	if err != nil {
-		return "", err
-	}
 	// 单一考核标准情况
 	if program != nil {
-		// 检查zip文件是否存在，存在则删除
-		zipFileName := filepath.Join(filePath, scoreStandardId+".zip")
-		zipfilePath := filepath.Join(fileUrl, scoreStandardId+".zip")
-		if _, err := os.Stat(zipFileName); !os.IsNotExist(err) {
-			os.RemoveAll(zipFileName)
-		}
-		// 在函数结束时清理临时目录
-		defer os.RemoveAll(filePath + scoreStandardId)
-		// 遍历ncProgram，创建文件并写入内容
-		for key, value := range program {
-			fileName := filepath.Join(filePath+scoreStandardId, key+".txt")
-			if _, err := os.Stat(zipFileName); !os.IsNotExist(err) {
-				os.RemoveAll(fileName)
-			}
-			err = os.MkdirAll(filePath+scoreStandardId, 0755)
-			if err != nil {
-				return "", err
-			}
-			err := os.WriteFile(fileName, []byte(value), 0666)
-			if err != nil {
-				return "", err
-			}
-		}
-		// 创建zip文件目录并添加内容到zip文件中
-		err = utils.AddDirToZip(zipFileName, filePath+scoreStandardId, scoreStandardId)
+		// 边打包边写入存储，已存在时覆盖
+		key := nowDate + "/" + examId + "/" + scoreStandardId + ".zip"
+		pr, pw := io.Pipe()
+		go func() {
+			pw.CloseWithError(WriteProgramZip(pw, scoreStandardId, program))
+		}()
+		err = store.Put(context.Background(), key, pr, -1, "application/zip")
+		pr.CloseWithError(err)
 		if err != nil {
-			log.Println("Error adding directory to zip:", err)
+			utils.Errorf("write program zip err: %v", err)
 			return "", err
 		}
-		return zipfilePath, nil
+		return store.URL(key), nil
 	}
 	return
 }
 
+// 打包程序的限制，程序内容全部在内存中
+const (
+	maxProgramBodySize = 16 << 20 //下载请求体最大字节数
+	maxProgramEntries  = 1000     //最多包含的程序数
+	maxProgramSize     = 8 << 20  //程序内容的总字节数
+)
+
+// WriteProgramZip 将程序内容打包为zip写入w，每个程序对应scoreStandardId目录下的一个txt文件。
+// 名称和大小的校验在写入任何内容之前完成
+func WriteProgramZip(w io.Writer, scoreStandardId string, program map[string]string) error {
+	if !validProgramName(scoreStandardId) {
+		return utils.NewError(utils.ErrInvalidParam, "非法的考核标准ID", nil)
+	}
+	if len(program) > maxProgramEntries {
+		return utils.NewError(utils.ErrTooLarge, fmt.Sprintf("程序数量超过%d个", maxProgramEntries), nil)
+	}
+	files := make(map[string]string, len(program))
+	var size int
+	for key, value := range program {
+		if !validProgramName(key) {
+			return utils.NewError(utils.ErrInvalidParam, "非法的程序名称", fmt.Errorf("%q", key))
+		}
+		if size += len(key) + len(value); size > maxProgramSize {
+			return utils.NewError(utils.ErrTooLarge, "程序内容过大", nil)
+		}
+		files[scoreStandardId+"/"+key+".txt"] = value
+	}
+	return utils.WriteZip(w, files, 0)
+}
+
+// validProgramName 名称直接用作压缩包中的路径，不能包含路径分隔符
+func validProgramName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, "/\\")
+}
+
+// DownloadProgram 将请求中的程序内容打包，直接以zip流返回
+func DownloadProgram(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ScoreStandardId string            `json:"score_standard_id"`
+		Program         map[string]string `json:"program"`
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxProgramBodySize)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			utils.HTTPError(w, r, utils.NewError(utils.ErrTooLarge, "请求内容过大", err))
+		} else {
+			utils.HTTPError(w, r, utils.NewError(utils.ErrInvalidParam, "请求格式错误", err))
+		}
+		return
+	}
+	if req.ScoreStandardId == "" || len(req.Program) == 0 {
+		utils.HTTPError(w, r, utils.NewError(utils.ErrInvalidParam, "缺少考核标准或程序内容", nil))
+		return
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": req.ScoreStandardId + ".zip"}))
+	err := WriteProgramZip(w, req.ScoreStandardId, req.Program)
+	if kind := utils.ErrorKind(err); kind == utils.ErrInvalidParam || kind == utils.ErrTooLarge {
+		// 校验在写入任何内容之前完成，此时仍可返回错误响应
+		w.Header().Del("Content-Disposition")
+		utils.HTTPError(w, r, err)
+	} else if err != nil {
+		// 响应已开始发送，只能记录日志
+		utils.WithContext(r.Context()).WithError(err).Error("write program zip err")
+	}
+}
+
+// 上传限制的默认值，配置未设置时使用
+const (
+	defaultMaxUploadSize = 100 << 20
+	defaultMaxZipSize    = 500 << 20
+	defaultMaxZipEntries = 10000
+)
+
+// defaultUploadExts 各上传类别默认允许的扩展名
+var defaultUploadExts = map[string][]string{
+	"zip":    {".zip", ".tar", ".tar.gz", ".tgz", ".tar.zst"},
+	"office": {".pdf", ".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx", ".txt", ".csv"},
+	"image":  {".jpg", ".jpeg", ".png", ".gif", ".webp"},
+	"video":  {".mp4", ".mov", ".webm"},
+}
+
+// uploadExts 上传类别允许的扩展名，未指定类别时按office处理
+func uploadExts(name string) []string {
+	if name == "" {
+		name = "office"
+	}
+	if exts, ok := config.Conf.Upload.Exts[name]; ok {
+		return exts
+	}
+	return defaultUploadExts[name]
+}
+
+// uploadExtractLimits 上传压缩包的解压限制，总大小和文件数可通过配置调整
+func uploadExtractLimits() utils.ExtractLimits {
+	limits := utils.DefaultExtractLimits
+	limits.MaxTotalSize = defaultMaxZipSize
+	limits.MaxEntries = defaultMaxZipEntries
+	if config.Conf.Upload.MaxZipSize > 0 {
+		limits.MaxTotalSize = config.Conf.Upload.MaxZipSize
+	}
+	if config.Conf.Upload.MaxZipEntries > 0 {
+		limits.MaxEntries = config.Conf.Upload.MaxZipEntries
+	}
+	return limits
+}
+
 func Upload(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	file, fileHeader, err := r.FormFile("file")
-	name := r.FormValue("name")
+	maxSize := config.Conf.Upload.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxUploadSize
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+	form, err := readUploadForm(r)
 	if err != nil {
-		log.Println("read file err", err)
+		utils.HTTPError(w, r, formFileError(err))
 		return
 	}
-	defer file.Close()
-	objectId := primitive.NewObjectID().Hex()
-	fileName := objectId + path.Ext(fileHeader.Filename)
-	nowDate := time.Now().Format("20060102")
-	filePath := config.Conf.UploadPath + config.Conf.UploadOfficeUrl + nowDate + "/"
-	err = utils.IsFolder(filePath)
+	defer form.Close()
+	record := &model.Upload{Name: form.values["name"], FileName: form.fileName, Uploader: form.values["uploader"]}
+	resp, err := saveUpload(r.Context(), record, form.file, form.size, form.hash)
 	if err != nil {
-		log.Println("mkdir err", err)
+		utils.HTTPError(w, r, err)
 		return
 	}
-	newFile, err := os.Create(filePath + fileName)
+	err = JSON(w, resp)
 	if err != nil {
-		log.Println("create file err", err)
-		return
+		utils.WithContext(r.Context()).WithError(err).Error("response err")
 	}
-	defer newFile.Close()
-	_, err = io.Copy(newFile, file)
+}
+
+// maxFormValueSize 上传表单中普通字段的最大字节数
+const maxFormValueSize = 1 << 10
+
+// uploadForm 流式读取的上传表单，文件写入临时文件的同时计算SHA-256，不用保存后再读一遍
+type uploadForm struct {
+	file     *os.File
+	fileName string
+	size     int64
+	hash     string
+	values   map[string]string
+}
+
+// readUploadForm 按顺序读取表单，只保存第一个名为file的文件，其余字段取第一个值
+func readUploadForm(r *http.Request) (*uploadForm, error) {
+	mr, err := r.MultipartReader()
 	if err != nil {
-		log.Println("write file err", err)
-		return
+		return nil, err
 	}
-	var resp interface{}
-	if name == "zip" && path.Ext(fileHeader.Filename) == ".zip" {
-		zipName, err := utils.Unzip2(filePath+fileName, filePath+objectId+"/")
+	form := &uploadForm{values: map[string]string{}}
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
 		if err != nil {
-			log.Println("unzip fail", err)
-			return
+			form.Close()
+			return nil, err
 		}
-		resp = &struct {
-			ZipPath   string `json:"zip_path"`
-			ModelPath string `json:"model_path"`
-		}{
-			ZipPath:   "/" + config.Conf.UploadOfficeUrl + nowDate + "/" + fileName,
-			ModelPath: "/" + config.Conf.UploadOfficeUrl + nowDate + "/" + objectId + "/" + zipName + "index.html",
+		name := part.FormName()
+		switch {
+		case name == "file" && part.FileName() != "" && form.file == nil:
+			err = form.saveFile(part)
+		case part.FileName() == "":
+			var b []byte
+			b, err = io.ReadAll(io.LimitReader(part, maxFormValueSize))
+			if _, ok := form.values[name]; !ok {
+				form.values[name] = string(b)
+			}
+		}
+		part.Close()
+		if err != nil {
+			form.Close()
+			return nil, err
 		}
-	} else {
-		resp = &UploadResp{Path: "/" + config.Conf.UploadOfficeUrl + nowDate + "/" + fileName}
 	}
-	err = JSON(w, resp)
+	if form.file == nil {
+		return nil, http.ErrMissingFile
+	}
+	return form, nil
+}
+
+// saveFile 将文件写入临时文件并计算哈希，完成后回到文件开头
+func (f *uploadForm) saveFile(part *multipart.Part) error {
+	tmp, err := os.CreateTemp("", "upload-*")
+	if err != nil {
+		return utils.NewError(utils.ErrStorage, "保存上传文件失败", err)
+	}
+	f.file = tmp
+	h := sha256.New()
+	if f.size, err = io.Copy(io.MultiWriter(utils.StorageWriter(tmp), h), part); err != nil {
+		return err
+	}
+	f.fileName = filepath.Base(part.FileName())
+	f.hash = hex.EncodeToString(h.Sum(nil))
+	if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		return utils.NewError(utils.ErrStorage, "保存上传文件失败", err)
+	}
+	return nil
+}
+
+// Close 删除临时文件
+func (f *uploadForm) Close() {
+	if f.file != nil {
+		f.file.Close()
+		os.Remove(f.file.Name())
+	}
+}
+
+// uploadFile 上传的文件，表单文件和本地文件都满足该接口
+type uploadFile interface {
+	io.ReadSeeker
+	io.ReaderAt
+}
+
+// saveUpload 校验文件类型后按内容哈希写入存储并保存上传记录，record需填好类别、文件名和上传人，hash为写入时计算的SHA-256
+func saveUpload(ctx context.Context, record *model.Upload, file uploadFile, size int64, hash string) (interface{}, error) {
+	name := record.Name
+	ext := archive.Ext(record.FileName)
+	head := make([]byte, utils.SniffLen)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, utils.NewError(utils.ErrInvalidParam, "读取上传文件失败", err)
+	}
+	if err = utils.CheckFileType(ext, uploadExts(name), head[:n]); err != nil {
+		return nil, err
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return nil, utils.NewError(utils.ErrInvalidParam, "读取上传文件失败", err)
+	}
+	blobId := upload.BlobId(hash, name == "zip")
+	key := upload.BlobKey(blobId, ext)
+	blob := &model.Blob{Id: blobId, Key: key, Size: size, ContentType: storage.ContentType(key)}
+	put := func(b *model.Blob) error {
+		return store.Put(ctx, b.Key, file, size, b.ContentType)
+	}
+	if name == "zip" {
+		// 每次都解压校验，内容已存在时不再写入存储
+		dir, err := os.MkdirTemp("", "upload-")
+		if err != nil {
+			return nil, utils.NewError(utils.ErrStorage, "创建临时目录失败", err)
+		}
+		defer os.RemoveAll(dir)
+		if _, err = archive.Extract(file, size, dir, uploadExtractLimits()); err != nil {
+			return nil, err
+		}
+		if blob.Index, err = archive.FindIndex(dir); err != nil {
+			return nil, utils.NewError(utils.ErrStorage, "读取解压文件失败", err)
+		}
+		blob.Prefix = upload.BlobPrefix(blobId)
+		put = func(b *model.Blob) error {
+			return saveArchive(ctx, b, file, dir)
+		}
+	}
+	if blob, err = upload.Acquire(ctx, store, blob, put); err != nil {
+		return nil, err
+	}
+	record.BlobId = blobId
+	record.Key = blob.Key
+	record.Size = size
+	record.ContentType = blob.ContentType
+	record.Index = blob.Index
+	if err = record.Create(record); err != nil {
+		if rerr := upload.Release(ctx, store, blobId); rerr != nil {
+			utils.WithContext(ctx).WithError(rerr).Warn("release blob err")
+		}
+		return nil, utils.NewError(utils.ErrStorage, "保存上传记录失败", err)
+	}
+	if name != "zip" {
+		return &UploadResp{Id: record.Id, Path: store.URL(record.Key)}, nil
+	}
+	return &struct {
+		Id        string `json:"id"`
+		ZipPath   string `json:"zip_path"`
+		ModelPath string `json:"model_path"`
+	}{
+		Id:        record.Id,
+		ZipPath:   store.URL(record.Key),
+		ModelPath: previewPath(record.BlobId, record.Index),
+	}, nil
+}
+
+// saveArchive 先写入解压后的目录再写入压缩包，压缩包存在即表示目录已完整。
+// 失败时不在这里删除，同一内容可能已被并发上传引用，由 upload.Acquire 撤销引用后按引用计数清理
+func saveArchive(ctx context.Context, b *model.Blob, file io.ReadSeeker, dir string) error {
+	_, err := file.Seek(0, io.SeekStart)
+	if err == nil {
+		err = storage.PutDir(ctx, store, dir, b.Prefix)
+	}
+	if err == nil {
+		err = store.Put(ctx, b.Key, file, b.Size, b.ContentType)
+	}
+	return err
+}
+
+// formFileError 区分缺少文件、超出大小限制和表单格式错误，已分类的错误原样返回
+func formFileError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	var e *utils.Error
+	switch {
+	case errors.As(err, &e):
+		return err
+	case errors.As(err, &maxBytesErr):
+		return utils.NewError(utils.ErrTooLarge, "上传文件过大", err)
+	case errors.Is(err, http.ErrMissingFile):
+		return utils.NewError(utils.ErrInvalidParam, "缺少上传文件", err)
+	default:
+		return utils.NewError(utils.ErrInvalidParam, "上传表单格式错误", err)
+	}
+}
+
+// newServer 创建HTTP服务，注册路由并套用监控指标、链路追踪、请求ID、访问日志和跨域中间件
+func newServer(h *health.Health, article pbs.ArticleServiceServer) *server.Server {
+	var s *server.Server
+	var mws []middleware.Middleware
+	route := func(r *http.Request) string { return s.Route(r) }
+	if metrics.Enabled() {
+		mws = append(mws, metrics.HTTP(route))
+	}
+	if trace.Enabled() {
+		mws = append(mws, trace.HTTP(route))
+	}
+	mws = append(mws, middleware.RequestId)
+	// X-User可由客户端伪造，只在网关会重写该头时信任
+	if config.Conf.TrustUserHeader {
+		mws = append(mws, middleware.User)
+	}
+	cors, err := middleware.CORS(config.Conf.CORS)
+	if err != nil {
+		utils.Fatalf("init cors err: %v", err)
+	}
+	mws = append(mws, middleware.AccessLog, cors)
+	s = server.New(config.Conf.HTTPPort, config.Conf.Server, mws...)
+	s.HandleFunc("/upload", Upload)
+	s.HandleFunc("POST /upload/chunked", InitiateChunked)
+	s.HandleFunc("GET /upload/chunked/{id}", GetChunked)
+	s.HandleFunc("PUT /upload/chunked/{id}/{index}", PutChunk)
+	s.HandleFunc("POST /upload/chunked/{id}/complete", CompleteChunked)
+	s.HandleFunc("DELETE /upload/chunked/{id}", AbortChunked)
+	s.HandleFunc("GET /uploads", ListUploads)
+	s.HandleFunc("GET /uploads/{id}", UploadDetail)
+	s.HandleFunc("DELETE /uploads/{id}", DeleteUpload)
+	s.HandleFunc("GET /preview/{blob}/{path...}", Preview)
+	s.HandleFunc("/program/download", DownloadProgram)
+	gateway.RegisterArticle(s, article)
+	s.HandleFunc("GET /healthz", h.Liveness)
+	s.HandleFunc("GET /readyz", h.Readiness)
+	if metrics.Enabled() {
+		s.Handle("GET "+metrics.Path(config.Conf.Metrics), metrics.Handler())
+	}
+	return s
+}
+
+// newHealth 注册依赖检查，etcd和文件存储只在启用时检查
+func newHealth() *health.Health {
+	h := health.New(config.Conf.Health)
+	h.Register("mongo", 0, health.Mongo(config.Db))
+	h.Register("redis", 0, health.Redis(utils.NewRedisCacheWithClient(config.Redis)))
+	if config.Etcd != nil {
+		h.Register("etcd", 0, health.Etcd(config.Etcd))
+	}
+	if store != nil {
+		h.Register("storage", 0, health.Storage(store))
+	}
+	return h
+}
+
+// newGRPCServer 创建gRPC服务，注册文章服务和 grpc.health.v1 健康检查，按配置记录调用指标和链路
+func newGRPCServer(h *health.Health, article pbs.ArticleServiceServer) *grpc.Server {
+	unary := []grpc.UnaryServerInterceptor{middleware.UnaryRequestId}
+	stream := []grpc.StreamServerInterceptor{middleware.StreamRequestId}
+	if config.Conf.TrustUserHeader {
+		unary = append(unary, middleware.UnaryUser)
+		stream = append(stream, middleware.StreamUser)
+	}
+	unary = append(unary, middleware.UnaryAccessLog)
+	stream = append(stream, middleware.StreamAccessLog)
+	if trace.Enabled() {
+		unary = append([]grpc.UnaryServerInterceptor{trace.UnaryServer}, unary...)
+		stream = append([]grpc.StreamServerInterceptor{trace.StreamServer}, stream...)
+	}
+	if metrics.Enabled() {
+		unary = append([]grpc.UnaryServerInterceptor{metrics.UnaryServer}, unary...)
+		stream = append([]grpc.StreamServerInterceptor{metrics.StreamServer}, stream...)
+	}
+	g := grpc.NewServer(grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(stream...))
+	pbs.RegisterArticleServiceServer(g, article)
+	healthpb.RegisterHealthServer(g, h.GRPC())
+	return g
+}
+
+// grpcAddr gRPC监听地址，可以只填端口
+func grpcAddr(port string) string {
+	if port == "" {
+		return defaultGRPCAddr
+	}
+	if !strings.Contains(port, ":") {
+		return ":" + port
+	}
+	return port
+}
+
+// defaultGRPCAddr 未配置port时gRPC的监听地址
+const defaultGRPCAddr = ":9090"
+
+// serve 启动HTTP和gRPC服务，收到退出信号后先将健康状态置为退出中，等待处理中的请求完成，再关闭Mongo、Redis和etcd连接
+func serve() {
+	if err := utils.InitLogger(config.Conf.Log); err != nil {
+		fmt.Println("Error init logger:", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := initStorage(ctx); err != nil {
+		utils.Fatalf("init storage err: %v", err)
+	}
+	initChunked(ctx)
+	// 文章类型的数字含义已变化，迁移完成前不能读写文章
+	migrateCtx, cancelMigrate := context.WithTimeout(ctx, time.Minute)
+	if err := model.MigrateArticleTypes(migrateCtx); err != nil {
+		utils.Fatalf("migrate article types err: %v", err)
+	}
+	cancelMigrate()
+	// 索引创建失败不影响启动，修订照常保存，只是查询变慢
+	indexCtx, cancelIndex := context.WithTimeout(ctx, 10*time.Second)
+	if err := model.EnsureRevisionIndexes(indexCtx); err != nil {
+		utils.Warnf("ensure article revision indexes err: %v", err)
+	}
+	cancelIndex()
+	// 单机部署不支持事务，文章修改后修订尽力保存
+	txnCtx, cancelTxn := context.WithTimeout(ctx, 10*time.Second)
+	if ok, err := model.DetectTransactions(txnCtx); err != nil {
+		utils.Warnf("detect mongo transactions err, article revisions saved without transactions: %v", err)
+	} else if !ok {
+		utils.Warnf("mongo is standalone, article revisions saved without transactions")
+	}
+	cancelTxn()
+	metrics.Init(config.Conf.Metrics)
+	tp, err := trace.Init(config.Conf.Trace)
+	if err != nil {
+		utils.Fatalf("init trace err: %v", err)
+	}
+	h := newHealth()
+	article := service.NewArticleService()
+	s := newServer(h, article)
+	if tp != nil {
+		// 最先注册最后执行，导出关闭过程中产生的span
+		s.OnShutdown("trace", tp.Shutdown)
+	}
+	s.OnShutdown("mongo", func(ctx context.Context) error {
+		return config.Db.Client().Disconnect(ctx)
+	})
+	s.OnShutdown("redis", func(ctx context.Context) error {
+		return config.Redis.Close()
+	})
+	if config.Etcd != nil {
+		s.OnShutdown("etcd", func(ctx context.Context) error {
+			return config.Etcd.Close()
+		})
+	}
+
+	g := newGRPCServer(h, article)
+	ln, err := net.Listen("tcp", grpcAddr(config.Conf.Port))
 	if err != nil {
-		log.Println("response err", err)
+		utils.Fatalf("grpc listen err: %v", err)
+	}
+	go func() {
+		utils.Infof("grpc server listening on %s", ln.Addr())
+		if err := g.Serve(ln); err != nil {
+			utils.Errorf("grpc server err: %v", err)
+			// gRPC服务异常退出时HTTP服务一起退出
+			cancel()
+		}
+	}()
+	s.OnShutdown("grpc", func(ctx context.Context) error {
+		done := make(chan struct{})
+		go func() {
+			g.GracefulStop()
+			close(done)
+		}()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			g.Stop()
+			return ctx.Err()
+		}
+	})
+	// 收到退出信号后立即生效，等待期间 /readyz 和gRPC健康检查返回 NOT_SERVING，Watch流收到后结束
+	s.BeforeShutdown(h.Shutdown)
+	if err := s.Run(ctx); err != nil {
+		utils.Fatalf("http server err: %v", err)
 	}
 }
 
 type UploadResp struct {
+	Id   string
 	Path string
 }
 
@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"net/http"
+	"playGround/middleware"
+	"strconv"
+	"time"
+)
+
+// unmatchedRoute 没有匹配路由的请求，避免按原始路径打标签导致指标数量无限增长
+const unmatchedRoute = "unmatched"
+
+// HTTP 记录请求数、耗时和正在处理的请求数，route返回请求匹配的路由模式
+func HTTP(route func(*http.Request) string) middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			httpInflight.Inc()
+			defer httpInflight.Dec()
+			sw := middleware.NewStatusWriter(w)
+			next.ServeHTTP(sw, r)
+			pattern := route(r)
+			if pattern == "" {
+				pattern = unmatchedRoute
+			}
+			httpRequests.WithLabelValues(r.Method, pattern, strconv.Itoa(sw.Status())).Inc()
+			httpDuration.WithLabelValues(r.Method, pattern).Observe(time.Since(start).Seconds())
+		})
+	}
+}
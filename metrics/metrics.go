@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"net/http"
+	"playGround/utils"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Config 监控指标配置
+type Config struct {
+	Enabled bool   `yaml:"enabled"` //是否启用，未启用时不注册路由和拦截器，埋点只有一次判空
+	Path    string `yaml:"path"`    //指标的HTTP路径，默认为 /metrics
+}
+
+// defaultPath 指标的默认HTTP路径
+const defaultPath = "/metrics"
+
+// namespace 指标名的前缀
+const namespace = "playground"
+
+// 未启用时均为nil
+var (
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	httpInflight prometheus.Gauge
+
+	grpcRequests *prometheus.CounterVec
+	grpcDuration *prometheus.HistogramVec
+
+	cacheRequests  *prometheus.CounterVec
+	lockWait       *prometheus.HistogramVec
+	limiterRejects *prometheus.CounterVec
+)
+
+// Init 按配置创建指标并接管utils的埋点，未启用时不做任何事
+func Init(cfg Config) {
+	if !cfg.Enabled {
+		return
+	}
+	registry = prometheus.NewRegistry()
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: "http", Name: "requests_total",
+		Help: "HTTP请求数，route为注册的路由",
+	}, []string{"method", "route", "code"})
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace, Subsystem: "http", Name: "request_duration_seconds",
+		Help: "HTTP请求耗时", Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+	httpInflight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace, Subsystem: "http", Name: "requests_in_flight",
+		Help: "正在处理的HTTP请求数",
+	})
+	grpcRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: "grpc", Name: "requests_total",
+		Help: "gRPC调用数",
+	}, []string{"method", "code"})
+	grpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace, Subsystem: "grpc", Name: "request_duration_seconds",
+		Help: "gRPC调用耗时", Buckets: prometheus.DefBuckets,
+	}, []string{"method"})
+	cacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: "cache", Name: "requests_total",
+		Help: "缓存查询数，result为hit/miss/error，命中率为hit占比",
+	}, []string{"cache", "result"})
+	lockWait = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace, Subsystem: "lock", Name: "wait_seconds",
+		Help: "加锁耗时，result为acquired/busy/error", Buckets: []float64{.001, .005, .01, .05, .1, .5, 1, 5, 10},
+	}, []string{"lock", "result"})
+	limiterRejects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: "limiter", Name: "rejected_total",
+		Help: "限流器拒绝次数，method为allow/wait",
+	}, []string{"method"})
+
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration, httpInflight,
+		grpcRequests, grpcDuration,
+		cacheRequests, lockWait, limiterRejects,
+	)
+	utils.SetMetrics(recorder{})
+}
+
+// Enabled 是否已启用
+func Enabled() bool {
+	return registry != nil
+}
+
+// Path 指标的HTTP路径
+func Path(cfg Config) string {
+	if cfg.Path == "" {
+		return defaultPath
+	}
+	return cfg.Path
+}
+
+// Handler 以Prometheus文本格式输出指标
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// recorder 实现 utils.Metrics
+type recorder struct{}
+
+func (recorder) CacheGet(cache, result string) {
+	cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+func (recorder) LockAcquire(lock, result string, wait time.Duration) {
+	lockWait.WithLabelValues(lock, result).Observe(wait.Seconds())
+}
+
+func (recorder) LimiterReject(method string) {
+	limiterRejects.WithLabelValues(method).Inc()
+}
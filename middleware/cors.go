@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSConfig 跨域配置
+type CORSConfig struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins"`   //允许的来源，支持 * 和 https://*.example.com 形式的子域名通配，为空时允许所有来源，允许携带凭证时必须明确列出
+	AllowedMethods   []string      `yaml:"allowed_methods"`   //允许的方法，为空时使用默认值
+	AllowedHeaders   []string      `yaml:"allowed_headers"`   //允许的请求头，* 表示允许所有请求头
+	ExposedHeaders   []string      `yaml:"exposed_headers"`   //允许浏览器读取的响应头
+	AllowCredentials bool          `yaml:"allow_credentials"` //是否允许携带cookie等凭证
+	MaxAge           time.Duration `yaml:"max_age"`           //预检结果的缓存时间
+}
+
+// 跨域配置的默认值
+var (
+	defaultCORSMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	defaultCORSHeaders = []string{"Content-Type", RequestIdHeader, UserHeader, "X-Chunk-Sha256"}
+	defaultCORSExposed = []string{RequestIdHeader}
+)
+
+type cors struct {
+	origins     []string
+	anyOrigin   bool
+	methods     map[string]bool
+	headers     map[string]bool
+	anyHeader   bool
+	allowMethod string
+	allowHeader string
+	exposed     string
+	credentials bool
+	maxAge      string
+}
+
+// CORS 按配置处理跨域请求，预检请求直接返回，不再交给后续处理。
+// 允许携带凭证时来源不能为空或 *，否则任意网站都能带着用户的凭证访问
+func CORS(cfg CORSConfig) (Middleware, error) {
+	c := &cors{
+		methods:     map[string]bool{},
+		headers:     map[string]bool{},
+		credentials: cfg.AllowCredentials,
+	}
+	if len(cfg.AllowedOrigins) == 0 {
+		if cfg.AllowCredentials {
+			return nil, errors.New("cors: allowed_origins must be listed when allow_credentials is set")
+		}
+		cfg.AllowedOrigins = []string{"*"}
+	}
+	for _, o := range cfg.AllowedOrigins {
+		if o == "*" {
+			if cfg.AllowCredentials {
+				return nil, errors.New("cors: allowed_origins can not be * when allow_credentials is set")
+			}
+			c.anyOrigin = true
+		}
+		c.origins = append(c.origins, strings.ToLower(o))
+	}
+	if len(cfg.AllowedMethods) == 0 {
+		cfg.AllowedMethods = defaultCORSMethods
+	}
+	methods := make([]string, 0, len(cfg.AllowedMethods))
+	for _, m := range cfg.AllowedMethods {
+		m = strings.ToUpper(m)
+		c.methods[m] = true
+		methods = append(methods, m)
+	}
+	c.allowMethod = strings.Join(methods, ", ")
+	if len(cfg.AllowedHeaders) == 0 {
+		cfg.AllowedHeaders = defaultCORSHeaders
+	}
+	for _, h := range cfg.AllowedHeaders {
+		if h == "*" {
+			c.anyHeader = true
+		}
+		c.headers[http.CanonicalHeaderKey(h)] = true
+	}
+	c.allowHeader = strings.Join(cfg.AllowedHeaders, ", ")
+	if len(cfg.ExposedHeaders) == 0 {
+		cfg.ExposedHeaders = defaultCORSExposed
+	}
+	c.exposed = strings.Join(cfg.ExposedHeaders, ", ")
+	if cfg.MaxAge > 0 {
+		c.maxAge = strconv.Itoa(int(cfg.MaxAge / time.Second))
+	}
+	return c.handler, nil
+}
+
+func (c *cors) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		origin := r.Header.Get("Origin")
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			h.Add("Vary", "Origin")
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+			// 不允许的预检请求不返回跨域头，由浏览器拒绝
+			if c.allowOrigin(origin) && c.allowPreflight(r) {
+				c.setOrigin(h, origin)
+				h.Set("Access-Control-Allow-Methods", c.allowMethod)
+				if c.anyHeader {
+					h.Set("Access-Control-Allow-Headers", r.Header.Get("Access-Control-Request-Headers"))
+				} else {
+					h.Set("Access-Control-Allow-Headers", c.allowHeader)
+				}
+				if c.maxAge != "" {
+					h.Set("Access-Control-Max-Age", c.maxAge)
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		h.Add("Vary", "Origin")
+		if origin != "" && c.allowOrigin(origin) {
+			c.setOrigin(h, origin)
+			if c.exposed != "" {
+				h.Set("Access-Control-Expose-Headers", c.exposed)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// setOrigin 允许所有来源时返回 *，否则回写请求的来源
+func (c *cors) setOrigin(h http.Header, origin string) {
+	if c.anyOrigin {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+	}
+	if c.credentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+func (c *cors) allowOrigin(origin string) bool {
+	if origin == "" {
+		return false
+	}
+	if c.anyOrigin {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	for _, o := range c.origins {
+		if o == origin {
+			return true
+		}
+		// https://*.example.com 匹配任意子域名，不匹配 example.com 本身
+		if i := strings.Index(o, "*."); i >= 0 {
+			prefix, suffix := o[:i], o[i+1:]
+			if len(origin) > len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// allowPreflight 预检请求的方法和请求头都在允许范围内
+func (c *cors) allowPreflight(r *http.Request) bool {
+	if !c.methods[strings.ToUpper(r.Header.Get("Access-Control-Request-Method"))] {
+		return false
+	}
+	if c.anyHeader {
+		return true
+	}
+	for _, h := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+		if h = strings.TrimSpace(h); h != "" && !c.headers[http.CanonicalHeaderKey(h)] {
+			return false
+		}
+	}
+	return true
+}
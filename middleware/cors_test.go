@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func corsServe(t *testing.T, cfg CORSConfig, r *http.Request) (*httptest.ResponseRecorder, bool) {
+	t.Helper()
+	mw, err := CORS(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	called := false
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.Header().Add("Vary", "Accept-Encoding")
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w, called
+}
+
+func preflight(origin, method, headers string) *http.Request {
+	r := httptest.NewRequest(http.MethodOptions, "/uploads", nil)
+	r.Header.Set("Origin", origin)
+	r.Header.Set("Access-Control-Request-Method", method)
+	if headers != "" {
+		r.Header.Set("Access-Control-Request-Headers", headers)
+	}
+	return r
+}
+
+func TestCORSConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  CORSConfig
+		ok   bool
+	}{
+		{"defaults", CORSConfig{}, true},
+		{"credentials with listed origins", CORSConfig{AllowedOrigins: []string{"https://*.example.com"}, AllowCredentials: true}, true},
+		{"credentials with empty origins", CORSConfig{AllowCredentials: true}, false},
+		{"credentials with wildcard", CORSConfig{AllowedOrigins: []string{"https://a.com", "*"}, AllowCredentials: true}, false},
+	}
+	for _, tt := range tests {
+		if _, err := CORS(tt.cfg); (err == nil) != tt.ok {
+			t.Errorf("%s: err = %v", tt.name, err)
+		}
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	cfg := CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.example.org"},
+		AllowedMethods:   []string{"get", "put"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+	tests := []struct {
+		name   string
+		r      *http.Request
+		origin string
+	}{
+		{"allowed", preflight("https://app.example.com", "PUT", "content-type, x-request-id"), "https://app.example.com"},
+		{"subdomain", preflight("https://a.example.org", "GET", ""), "https://a.example.org"},
+		{"bare domain of wildcard", preflight("https://example.org", "GET", ""), ""},
+		{"origin not allowed", preflight("https://evil.com", "GET", ""), ""},
+		{"method not allowed", preflight("https://app.example.com", "DELETE", ""), ""},
+		{"header not allowed", preflight("https://app.example.com", "PUT", "X-Secret"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, called := corsServe(t, cfg, tt.r)
+			// 预检请求无论是否允许都直接返回
+			if called || w.Code != http.StatusNoContent {
+				t.Fatalf("called = %v, status = %d", called, w.Code)
+			}
+			h := w.Header()
+			if got := h.Get("Access-Control-Allow-Origin"); got != tt.origin {
+				t.Fatalf("allow origin = %q, want %q", got, tt.origin)
+			}
+			if tt.origin == "" {
+				if h.Get("Access-Control-Allow-Methods") != "" || h.Get("Access-Control-Allow-Credentials") != "" {
+					t.Fatalf("rejected preflight headers = %v", h)
+				}
+				return
+			}
+			if h.Get("Access-Control-Allow-Methods") != "GET, PUT" || h.Get("Access-Control-Allow-Credentials") != "true" || h.Get("Access-Control-Max-Age") != "600" {
+				t.Fatalf("preflight headers = %v", h)
+			}
+		})
+	}
+}
+
+func TestCORSRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         CORSConfig
+		origin      string
+		allow       string
+		credentials string
+	}{
+		{"wildcard", CORSConfig{}, "https://a.com", "*", ""},
+		{"listed with credentials", CORSConfig{AllowedOrigins: []string{"https://a.com"}, AllowCredentials: true}, "https://A.com", "https://A.com", "true"},
+		{"not listed", CORSConfig{AllowedOrigins: []string{"https://a.com"}, AllowCredentials: true}, "https://b.com", "", ""},
+		{"no origin", CORSConfig{}, "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/uploads", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			w, called := corsServe(t, tt.cfg, r)
+			if !called {
+				t.Fatal("request not passed to next handler")
+			}
+			h := w.Header()
+			if h.Get("Access-Control-Allow-Origin") != tt.allow || h.Get("Access-Control-Allow-Credentials") != tt.credentials {
+				t.Fatalf("headers = %v", h)
+			}
+			// Vary追加在已有值之后，不覆盖后续处理设置的值
+			if got := h.Values("Vary"); !reflect.DeepEqual(got, []string{"Origin", "Accept-Encoding"}) {
+				t.Fatalf("vary = %v", got)
+			}
+		})
+	}
+}
+
+func TestCORSVaryPreserved(t *testing.T) {
+	mw, err := CORS(CORSConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	w.Header().Set("Vary", "Accept-Encoding")
+	mw(http.NotFoundHandler()).ServeHTTP(w, preflight("https://a.com", "GET", ""))
+	want := []string{"Accept-Encoding", "Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"}
+	if got := w.Header().Values("Vary"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("vary = %v", got)
+	}
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"context"
+	"playGround/utils"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIdMetadata gRPC元数据中的请求ID，元数据键统一为小写
+var requestIdMetadata = strings.ToLower(RequestIdHeader)
+
+// requestIdFromMetadata 沿用调用方传入的请求ID或生成新的ID，并通过响应头返回
+func requestIdFromMetadata(ctx context.Context) context.Context {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get(requestIdMetadata); len(vals) > 0 {
+			id = vals[0]
+		}
+	}
+	if !validRequestId(id) {
+		id = newRequestId()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(requestIdMetadata, id))
+	return utils.ContextWithRequestId(ctx, id)
+}
+
+// userMetadata gRPC元数据中的操作人
+var userMetadata = strings.ToLower(UserHeader)
+
+// userFromMetadata 将元数据中的操作人写入上下文，不合法时忽略
+func userFromMetadata(ctx context.Context) context.Context {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get(userMetadata); len(vals) > 0 {
+			if user, ok := parseUser(vals[0]); ok {
+				return utils.ContextWithUser(ctx, user)
+			}
+		}
+	}
+	return ctx
+}
+
+// UnaryUser 一元调用的操作人拦截器
+func UnaryUser(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(userFromMetadata(ctx), req)
+}
+
+// StreamUser 流式调用的操作人拦截器
+func StreamUser(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &wrappedStream{ServerStream: ss, ctx: userFromMetadata(ss.Context())})
+}
+
+// UnaryRequestId 一元调用的请求ID拦截器
+func UnaryRequestId(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(requestIdFromMetadata(ctx), req)
+}
+
+// StreamRequestId 流式调用的请求ID拦截器
+func StreamRequestId(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &wrappedStream{ServerStream: ss, ctx: requestIdFromMetadata(ss.Context())})
+}
+
+// UnaryAccessLog 一元调用的访问日志拦截器
+func UnaryAccessLog(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	var size int
+	if s, ok := resp.(interface{ Size() int }); ok && err == nil {
+		size = s.Size()
+	}
+	grpcAccessLog(ctx, info.FullMethod, start, err).WithField("bytes", size).Info("grpc access")
+	return resp, err
+}
+
+// StreamAccessLog 流式调用的访问日志拦截器
+func StreamAccessLog(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	cs := &countingStream{ServerStream: ss}
+	err := handler(srv, cs)
+	grpcAccessLog(ss.Context(), info.FullMethod, start, err).WithFields(utils.Fields{
+		"bytes":          cs.sent.Load(),
+		"bytes_received": cs.received.Load(),
+	}).Info("grpc access")
+	return err
+}
+
+func grpcAccessLog(ctx context.Context, method string, start time.Time, err error) *logrus.Entry {
+	entry := utils.WithContext(ctx).WithFields(utils.Fields{
+		"rpc":     method,
+		"status":  status.Code(err).String(),
+		"latency": time.Since(start).String(),
+	})
+	if err != nil {
+		entry = entry.WithError(err)
+	}
+	return entry
+}
+
+// wrappedStream 替换流的上下文
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *wrappedStream) Context() context.Context {
+	return s.ctx
+}
+
+// countingStream 统计流中成功收发的消息字节数，发送和接收可能在不同协程中进行
+type countingStream struct {
+	grpc.ServerStream
+	sent     atomic.Int64
+	received atomic.Int64
+}
+
+// msgSize 消息编码后的字节数，gogoproto生成的消息都实现了Size
+func msgSize(m interface{}) int64 {
+	if s, ok := m.(interface{ Size() int }); ok {
+		return int64(s.Size())
+	}
+	return 0
+}
+
+func (s *countingStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent.Add(msgSize(m))
+	}
+	return err
+}
+
+func (s *countingStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received.Add(msgSize(m))
+	}
+	return err
+}
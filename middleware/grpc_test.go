@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"playGround/pbs"
+	"playGround/utils"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+// fakeStream 接收时依次返回recv中的消息，之后返回err
+type fakeStream struct {
+	grpc.ServerStream
+	recv []*pbs.Article
+	err  error
+}
+
+func (s *fakeStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *fakeStream) SendMsg(m interface{}) error {
+	return s.err
+}
+
+func (s *fakeStream) RecvMsg(m interface{}) error {
+	if len(s.recv) == 0 {
+		return errors.New("EOF")
+	}
+	*m.(*pbs.Article) = *s.recv[0]
+	s.recv = s.recv[1:]
+	return nil
+}
+
+// captureHook 记录日志条目
+type captureHook struct {
+	entries []*logrus.Entry
+}
+
+func (h *captureHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *captureHook) Fire(e *logrus.Entry) error {
+	h.entries = append(h.entries, e)
+	return nil
+}
+
+func TestStreamAccessLogBytes(t *testing.T) {
+	hook := new(captureHook)
+	utils.Logger().AddHook(hook)
+	defer utils.Logger().ReplaceHooks(make(logrus.LevelHooks))
+
+	in := &pbs.Article{Title: "hello"}
+	out := &pbs.Article{Content: "world!"}
+	ss := &fakeStream{recv: []*pbs.Article{in, in}}
+	info := &grpc.StreamServerInfo{FullMethod: "/pbs.ArticleService/Watch"}
+	err := StreamAccessLog(nil, ss, info, func(srv interface{}, stream grpc.ServerStream) error {
+		for {
+			msg := new(pbs.Article)
+			if err := stream.RecvMsg(msg); err != nil {
+				break
+			}
+			stream.SendMsg(out)
+		}
+		// 发送失败的消息不计入
+		ss.err = errors.New("broken pipe")
+		return stream.SendMsg(out)
+	})
+	if err == nil || len(hook.entries) != 1 {
+		t.Fatalf("err = %v, entries = %d", err, len(hook.entries))
+	}
+	fields := hook.entries[0].Data
+	if fields["bytes"] != int64(2*out.Size()) || fields["bytes_received"] != int64(2*in.Size()) {
+		t.Fatalf("fields = %v", fields)
+	}
+}
@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"playGround/utils"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RequestIdHeader 请求ID的HTTP头
+const RequestIdHeader = "X-Request-ID"
+
+// UserHeader 操作人的HTTP头，由前置的鉴权网关设置，非ASCII字符需URL编码。
+// 客户端可以伪造该头，网关必须先删除客户端传入的值，否则不能启用 User 中间件
+const UserHeader = "X-User"
+
+// Middleware HTTP中间件
+type Middleware func(http.Handler) http.Handler
+
+// Chain 依次套用中间件，第一个中间件在最外层
+func Chain(h http.Handler, mws ...Middleware) http.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// RequestId 沿用客户端传入的请求ID或生成新的ID，写入上下文并在响应头中返回
+func RequestId(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIdHeader)
+		if !validRequestId(id) {
+			id = newRequestId()
+		}
+		w.Header().Set(RequestIdHeader, id)
+		next.ServeHTTP(w, r.WithContext(utils.ContextWithRequestId(r.Context(), id)))
+	})
+}
+
+// User 将请求头中的操作人写入上下文，用于记录文章的修改人，不合法时忽略。只能部署在会重写该头的网关之后
+func User(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, ok := parseUser(r.Header.Get(UserHeader)); ok {
+			r = r.WithContext(utils.ContextWithUser(r.Context(), user))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AccessLog 每个请求记录一行访问日志
+func AccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := NewStatusWriter(w)
+		next.ServeHTTP(sw, r)
+		utils.WithContext(r.Context()).WithFields(utils.Fields{
+			"method":  r.Method,
+			"path":    r.URL.Path,
+			"status":  sw.Status(),
+			"latency": time.Since(start).String(),
+			"bytes":   sw.bytes,
+			"remote":  r.RemoteAddr,
+		}).Info("http access")
+	})
+}
+
+// StatusWriter 记录响应状态码和字节数
+type StatusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+// NewStatusWriter 包装ResponseWriter，供需要响应状态码的中间件使用
+func NewStatusWriter(w http.ResponseWriter) *StatusWriter {
+	return &StatusWriter{ResponseWriter: w}
+}
+
+func (w *StatusWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *StatusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+func (w *StatusWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// Bytes 已写入的响应字节数
+func (w *StatusWriter) Bytes() int64 {
+	return w.bytes
+}
+
+// Unwrap 供 http.ResponseController 获取原始ResponseWriter
+func (w *StatusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *StatusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// maxUserLen 操作人的最大字符数
+const maxUserLen = 64
+
+// parseUser 解码URL编码的操作人，只接受长度有限且不含控制字符的UTF-8字符串，防止日志注入
+func parseUser(v string) (string, bool) {
+	user, err := url.PathUnescape(strings.TrimSpace(v))
+	if err != nil || user == "" || !utf8.ValidString(user) || utf8.RuneCountInString(user) > maxUserLen {
+		return "", false
+	}
+	for _, c := range user {
+		if unicode.IsControl(c) {
+			return "", false
+		}
+	}
+	return user, true
+}
+
+func newRequestId() string {
+	return primitive.NewObjectID().Hex()
+}
+
+// validRequestId 只接受长度有限的可见ASCII字符，防止日志注入
+func validRequestId(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -2,6 +2,8 @@ package model
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"playGround/config"
 	"playGround/pbs"
 	"playGround/utils"
@@ -20,18 +22,41 @@ type Article struct {
 
 var ArticleColl *mongo.Collection //集合
 
+// VersionConflictError 修改时传入的版本号与数据库中的不一致
+type VersionConflictError struct {
+	Current int64 //数据库中的当前版本号
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("version conflict, current version %d", e.Current)
+}
+
 func init() {
 	ArticleColl = config.Db.Collection("article")
 }
 
-func (a *Article) Create(data *pbs.Article) error {
+// Create 新建文章，同时保存第一个版本的修订
+func (a *Article) Create(ctx context.Context, data *pbs.Article) error {
 	data.Id = primitive.NewObjectID().Hex()
 	data.CreatedAt = time.Now().Unix()
-	return a.SetColl(ArticleColl).Add(data)
+	data.Version = 1
+	return writeArticle(ctx, func(ctx context.Context) ([]*pbs.Revision, error) {
+		if err := a.Model.WithContext(ctx).SetColl(ArticleColl).Add(data); err != nil {
+			return nil, err
+		}
+		rev := &pbs.Revision{Action: RevisionCreate}
+		fillRevision(ctx, rev, data, nil)
+		return []*pbs.Revision{rev}, nil
+	})
 }
 
-func (a *Article) GetArticleList(page, pageSize int64) (rs []*pbs.Article, count int64, err error) {
+// GetArticleList 文章列表，types不为空时只返回这些类型的文章
+func (a *Article) GetArticleList(ctx context.Context, page, pageSize int64, types []pbs.ArticleType) (rs []*pbs.Article, count int64, err error) {
 	var filter = bson.M{"deleted_at": 0}
+	if len(types) > 0 {
+		//类型按数字保存，$in 同样匹配旧文档中的int64和double
+		filter["article_type"] = bson.M{"$in": types}
+	}
 	opt := &options.FindOptions{}
 	if page != -1 && page > 0 { //page等于-1时不分页
 		var offset = (page - 1) * pageSize
@@ -39,27 +64,106 @@ func (a *Article) GetArticleList(page, pageSize int64) (rs []*pbs.Article, count
 		opt.SetSkip(offset)
 	}
 	opt.SetSort(bson.M{"_id": -1})
-	count, _ = ArticleColl.CountDocuments(Context, filter)
-	query, err := ArticleColl.Find(Context, filter, opt)
+	countCtx, end := StartSpan(ctx, ArticleColl, "count")
+	count, err = ArticleColl.CountDocuments(countCtx, filter)
+	end(err)
 	if err != nil {
 		return
 	}
-	err = query.All(Context, &rs)
+	findCtx, end := StartSpan(ctx, ArticleColl, "find")
+	defer func() { end(err) }()
+	query, err := ArticleColl.Find(findCtx, filter, opt)
+	if err != nil {
+		return
+	}
+	err = query.All(findCtx, &rs)
 	return
 }
 
-func (a *Article) View(articleId string) (rs *pbs.Article, err error) {
+func (a *Article) View(ctx context.Context, articleId string) (rs *pbs.Article, err error) {
 	rs = new(pbs.Article)
-	err = ArticleColl.FindOne(context.Background(), bson.M{"_id": articleId}).Decode(&rs)
+	ctx, end := StartSpan(ctx, ArticleColl, "findOne")
+	err = ArticleColl.FindOne(ctx, bson.M{"_id": articleId, "deleted_at": 0}).Decode(&rs)
+	if err == mongo.ErrNoDocuments {
+		end(nil)
+	} else {
+		end(err)
+	}
 	return
 }
 
-func (c *Article) Edit(data *pbs.Article) error {
+// Edit 修改所有非零值字段，成功后保存修订
+func (c *Article) Edit(ctx context.Context, data *pbs.Article) error {
 	data.UpdatedAt = time.Now().Unix()
 	update := utils.Struct2Map(*data)
 	delete(update, "_id")
 	delete(update, "created_at") //不能修改ID
 	delete(update, "_id,omitempty")
-	_, err := ArticleColl.UpdateOne(Context, bson.M{"_id": data.Id}, bson.M{"$set": update})
+	delete(update, "deleted_at") //删除走Delete
+	delete(update, "-")          //update_mask不保存
+	return c.update(ctx, data.Id, data.Version, update, &pbs.Revision{Action: RevisionUpdate})
+}
+
+// EditFields 只修改paths中列出的字段，零值同样写入，成功后保存修订
+func (c *Article) EditFields(ctx context.Context, data *pbs.Article, paths []string) error {
+	set, err := FieldsByPath(data, paths)
+	if err != nil {
+		return err
+	}
+	set["updated_at"] = time.Now().Unix()
+	return c.update(ctx, data.Id, data.Version, set, &pbs.Revision{Action: RevisionUpdate})
+}
+
+// update 版本号一致时修改文章并将版本号加1，用修改后的内容填充rev并保存，支持事务时与修订在同一事务中保存。
+// 文章不存在时返回 mongo.ErrNoDocuments，版本不一致时返回 *VersionConflictError
+func (c *Article) update(ctx context.Context, articleId string, version int64, set bson.M, rev *pbs.Revision) error {
+	filter := bson.M{"_id": articleId, "deleted_at": 0, "version": version}
+	if version == 0 {
+		//旧文档没有version字段
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	}
+	delete(set, "version")
+	err := writeArticle(ctx, func(ctx context.Context) ([]*pbs.Revision, error) {
+		// 取回修改前的文章，用于生成修订
+		before := new(pbs.Article)
+		updateCtx, end := StartSpan(ctx, ArticleColl, "findOneAndUpdate")
+		err := ArticleColl.FindOneAndUpdate(updateCtx, filter, bson.M{"$set": set, "$inc": bson.M{"version": 1}}).Decode(before)
+		if err == mongo.ErrNoDocuments {
+			end(nil)
+			return nil, err
+		}
+		end(err)
+		if err != nil {
+			return nil, err
+		}
+		after, err := applySet(before, set)
+		if err != nil {
+			return nil, fmt.Errorf("build article revision: %w", err)
+		}
+		var revs []*pbs.Revision
+		if before.Version == 0 {
+			revs = append(revs, baselineRevision(before))
+		}
+		fillRevision(ctx, rev, after, before)
+		return append(revs, rev), nil
+	})
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+	current, err := c.View(ctx, articleId)
+	if err != nil {
+		return err
+	}
+	return &VersionConflictError{Current: current.Version}
+}
+
+// Delete 软删除，文章不存在或已删除时返回 mongo.ErrNoDocuments
+func (c *Article) Delete(ctx context.Context, articleId string) error {
+	ctx, end := StartSpan(ctx, ArticleColl, "update")
+	res, err := ArticleColl.UpdateOne(ctx, bson.M{"_id": articleId, "deleted_at": 0}, bson.M{"$set": bson.M{"deleted_at": time.Now().Unix()}})
+	end(err)
+	if err == nil && res.MatchedCount == 0 {
+		err = mongo.ErrNoDocuments
+	}
 	return err
 }
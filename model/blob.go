@@ -0,0 +1,102 @@
+package model
+
+import (
+	"errors"
+	"playGround/config"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Blob 按内容哈希保存的文件，多条上传记录可以引用同一个文件
+type Blob struct {
+	Id          string `json:"id" bson:"_id"`                            //文件内容的SHA-256，解压保存的压缩包带后缀，见 upload.BlobId
+	Key         string `json:"key" bson:"key"`                           //存储中的key
+	Prefix      string `json:"prefix,omitempty" bson:"prefix,omitempty"` //压缩包解压后的目录
+	Index       string `json:"index,omitempty" bson:"index,omitempty"`   //压缩包中入口页面的相对路径
+	Size        int64  `json:"size" bson:"size"`
+	ContentType string `json:"content_type" bson:"content_type"`
+	RefCount    int64  `json:"ref_count" bson:"ref_count"` //引用计数
+	Deleting    bool   `json:"-" bson:"deleting"`          //引用归零后正在删除存储中的文件
+	CreatedAt   int64  `json:"created_at" bson:"created_at"`
+}
+
+var BlobColl *mongo.Collection //集合
+
+// 文件正在删除时等待重试的间隔和次数
+const (
+	blobRetryInterval = 100 * time.Millisecond
+	blobRetryTimes    = 50
+)
+
+func init() {
+	BlobColl = config.Db.Collection("blob")
+}
+
+// Acquire 引用计数加1，记录不存在时以b的内容创建，返回最新的记录
+func (b *Blob) Acquire() (rs *Blob, err error) {
+	filter := bson.M{"_id": b.Id, "deleting": bson.M{"$ne": true}}
+	update := bson.M{
+		"$inc": bson.M{"ref_count": 1},
+		"$setOnInsert": bson.M{
+			"key":          b.Key,
+			"prefix":       b.Prefix,
+			"index":        b.Index,
+			"size":         b.Size,
+			"content_type": b.ContentType,
+			"deleting":     false,
+			"created_at":   time.Now().Unix(),
+		},
+	}
+	opt := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	for i := 0; i < blobRetryTimes; i++ {
+		rs = new(Blob)
+		err = BlobColl.FindOneAndUpdate(Context, filter, update, opt).Decode(rs)
+		// 同一内容的旧文件正在删除，插入时主键冲突，等删除完成后重试
+		if !mongo.IsDuplicateKeyError(err) {
+			return
+		}
+		time.Sleep(blobRetryInterval)
+	}
+	return nil, err
+}
+
+// Release 引用计数减1，归零时标记为删除中并返回该记录，调用方删除文件后需调用 Remove
+func (b *Blob) Release(blobId string) (*Blob, error) {
+	rs := new(Blob)
+	err := BlobColl.FindOneAndUpdate(Context,
+		bson.M{"_id": blobId, "ref_count": bson.M{"$gt": 0}},
+		bson.M{"$inc": bson.M{"ref_count": -1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(rs)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil || rs.RefCount > 0 {
+		return nil, err
+	}
+	// 减到0后可能又被引用，只有仍为0时才标记删除
+	res, err := BlobColl.UpdateOne(Context,
+		bson.M{"_id": blobId, "ref_count": 0, "deleting": false},
+		bson.M{"$set": bson.M{"deleting": true}},
+	)
+	if err != nil || res.ModifiedCount == 0 {
+		return nil, err
+	}
+	rs.Deleting = true
+	return rs, nil
+}
+
+// Remove 删除标记为删除中的记录
+func (b *Blob) Remove(blobId string) error {
+	_, err := BlobColl.DeleteOne(Context, bson.M{"_id": blobId, "deleting": true})
+	return err
+}
+
+// Unmark 删除文件失败时取消删除标记，记录保留为无引用状态
+func (b *Blob) Unmark(blobId string) error {
+	_, err := BlobColl.UpdateOne(Context, bson.M{"_id": blobId, "deleting": true}, bson.M{"$set": bson.M{"deleting": false}})
+	return err
+}
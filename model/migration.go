@@ -0,0 +1,41 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"playGround/config"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var MigrationColl *mongo.Collection //已执行的数据迁移
+
+// articleTypeMigration 文章类型增加 ARTICLE_TYPE_UNSPECIFIED = 0 的迁移
+const articleTypeMigration = "article_type_unspecified"
+
+func init() {
+	MigrationColl = config.Db.Collection("migration")
+}
+
+// MigrateArticleTypes 文章类型枚举增加了 ARTICLE_TYPE_UNSPECIFIED = 0，按原来 NEWS=0、AWARD=1、NOTICE=2 保存的文章和修订统一加1。
+// 先插入迁移记录，多个实例同时启动时只有插入成功的实例执行；中途失败时记录没有 finished_at，需核对数据后删除记录再重试，避免重复加1
+func MigrateArticleTypes(ctx context.Context) error {
+	_, err := MigrationColl.InsertOne(ctx, bson.M{"_id": articleTypeMigration, "started_at": time.Now().Unix()})
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// 没有该字段的旧文章原来按NEWS处理，$inc后同样为 ARTICLE_TYPE_NEWS
+	inc := bson.M{"$inc": bson.M{"article_type": 1}}
+	for _, coll := range []*mongo.Collection{ArticleColl, RevisionColl} {
+		if _, err = coll.UpdateMany(ctx, bson.M{}, inc); err != nil {
+			return fmt.Errorf("migrate %s article_type: %w", coll.Name(), err)
+		}
+	}
+	_, err = MigrationColl.UpdateByID(ctx, articleTypeMigration, bson.M{"$set": bson.M{"finished_at": time.Now().Unix()}})
+	return err
+}
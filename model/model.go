@@ -3,6 +3,8 @@ package model
 import (
 	"context"
 	"errors"
+	"fmt"
+	"playGround/utils"
 	"reflect"
 	"strings"
 
@@ -26,6 +28,7 @@ type CModel interface {
 type Model struct {
 	Coll        *mongo.Collection `json:"-" bson:"-"`
 	ChangeField []string          `json:"-" bson:"-"`
+	ctx         context.Context   //为nil时使用 context.Background()
 }
 
 func (this *Model) ToMap(v CModel) map[string]interface{} {
@@ -72,13 +75,64 @@ func (this *Model) SetColl(coll *mongo.Collection) CModel {
 	return this
 }
 
+// 使用ctx执行数据库操作，返回副本，不影响共用的Model
+func (this *Model) WithContext(ctx context.Context) *Model {
+	m := *this
+	m.ctx = ctx
+	return &m
+}
+
+// 开始一个数据库操作的子span
+func (this *Model) span(op string) (context.Context, func(error)) {
+	ctx := this.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return StartSpan(ctx, this.Coll, op)
+}
+
+// StartSpan 开始一个Mongo操作的子span，未启用链路追踪时返回原上下文
+func StartSpan(ctx context.Context, coll *mongo.Collection, op string) (context.Context, func(error)) {
+	return utils.StartSpan(ctx, "mongo "+op+" "+coll.Name(),
+		"db.system", "mongodb", "db.name", coll.Database().Name(), "db.collection", coll.Name(), "db.operation", op)
+}
+
+// FieldsByPath 按JSON字段名取出结构体中的字段，返回以bson字段名为键的值，包括零值
+func FieldsByPath(v interface{}, paths []string) (bson.M, error) {
+	val := reflect.Indirect(reflect.ValueOf(v))
+	typ := val.Type()
+	rs := bson.M{}
+	for _, p := range paths {
+		found := false
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			if strings.Split(f.Tag.Get("json"), ",")[0] != p {
+				continue
+			}
+			key := strings.Split(f.Tag.Get("bson"), ",")[0]
+			if key == "" || key == "-" {
+				break
+			}
+			rs[key] = val.Field(i).Interface()
+			found = true
+			break
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown field %q", p)
+		}
+	}
+	return rs, nil
+}
+
 // 数据入数据库
 func (this *Model) AddMany(data []interface{}) error {
 	if this.Coll == nil {
 		return errors.New("数据库未连接")
 	}
 
-	_, err := this.Coll.InsertMany(context.Background(), data)
+	ctx, end := this.span("insertMany")
+	_, err := this.Coll.InsertMany(ctx, data)
+	end(err)
 	if err != nil {
 		return err
 	}
@@ -90,7 +144,9 @@ func (this *Model) Add(data interface{}) error {
 	if this.Coll == nil {
 		return errors.New("数据库未连接")
 	}
-	_, err := this.Coll.InsertOne(context.Background(), data)
+	ctx, end := this.span("insert")
+	_, err := this.Coll.InsertOne(ctx, data)
+	end(err)
 	if err != nil {
 		return err
 	}
@@ -103,7 +159,9 @@ func (this *Model) Remove(where CModel) error {
 		return errors.New("数据库未连接")
 	}
 	filter := where.ToMap(where)
-	_, err := this.Coll.DeleteMany(context.Background(), filter)
+	ctx, end := this.span("delete")
+	_, err := this.Coll.DeleteMany(ctx, filter)
+	end(err)
 	if err != nil {
 		return err
 	}
@@ -119,7 +177,9 @@ func (this *Model) Update(where CModel, data CModel) error {
 
 	set := bson.M{"$set": data.ToMap(data)} //要修改的数据字段
 
-	_, err := this.Coll.UpdateMany(context.Background(), filter, set)
+	ctx, end := this.span("update")
+	_, err := this.Coll.UpdateMany(ctx, filter, set)
+	end(err)
 	if err != nil {
 		return err
 	}
@@ -133,7 +193,13 @@ func (this *Model) One(where CModel, v interface{}) error {
 		return errors.New("数据库未连接")
 	}
 	filter := where.ToMap(where)
-	err := this.Coll.FindOne(context.Background(), filter).Decode(v)
+	ctx, end := this.span("findOne")
+	err := this.Coll.FindOne(ctx, filter).Decode(v)
+	if err == mongo.ErrNoDocuments {
+		end(nil)
+	} else {
+		end(err)
+	}
 	if err != nil {
 		return err
 	}
@@ -147,10 +213,11 @@ func (this *Model) All(where CModel, v interface{}) error {
 	}
 	filter := where.ToMap(where)
 	var opt = new(options.FindOptions)
-	query, err := this.Coll.Find(context.Background(), filter, opt.SetSort(bson.M{"_id": -1}))
-	if err != nil {
-		return err
+	ctx, end := this.span("find")
+	query, err := this.Coll.Find(ctx, filter, opt.SetSort(bson.M{"_id": -1}))
+	if err == nil {
+		err = query.All(ctx, v)
 	}
-	err = query.All(context.Background(), v)
+	end(err)
 	return err
 }
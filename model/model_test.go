@@ -0,0 +1,27 @@
+package model
+
+import (
+	"playGround/pbs"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestFieldsByPath(t *testing.T) {
+	a := &pbs.Article{Title: "t", CoverImg: "", ArticleType: pbs.ArticleType_ARTICLE_TYPE_AWARD}
+	got, err := FieldsByPath(a, []string{"title", "cover_img", "article_type"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 零值同样返回，用于清空字段
+	want := bson.M{"title": "t", "cover_img": "", "article_type": pbs.ArticleType_ARTICLE_TYPE_AWARD}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("fields = %v", got)
+	}
+	for _, p := range []string{"nope", "update_mask", "Title"} {
+		if _, err = FieldsByPath(a, []string{p}); err == nil {
+			t.Errorf("path %q accepted", p)
+		}
+	}
+}
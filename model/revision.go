@@ -0,0 +1,248 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"playGround/config"
+	"playGround/pbs"
+	"playGround/utils"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var RevisionColl *mongo.Collection //文章修订集合，每次新建、修改、回滚后保存一份快照
+
+// 修订的操作类型
+const (
+	RevisionCreate   = "create"
+	RevisionUpdate   = "update"
+	RevisionRollback = "rollback"
+	RevisionBaseline = "baseline" //首次修改没有版本号的旧文章时，保存修改前的内容
+)
+
+// ErrRevisionNotFound 文章没有该版本的修订
+var ErrRevisionNotFound = errors.New("revision not found")
+
+func init() {
+	RevisionColl = config.Db.Collection("article_revision")
+}
+
+// EnsureRevisionIndexes 创建修订集合的索引，同一文章的版本号唯一，列表按版本号倒序
+func EnsureRevisionIndexes(ctx context.Context) error {
+	_, err := RevisionColl.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "article_id", Value: 1}, {Key: "version", Value: -1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// Revisions 文章的修订列表，按版本号倒序，不返回正文
+func (a *Article) Revisions(ctx context.Context, articleId string, page, pageSize int64) (rs []*pbs.Revision, count int64, err error) {
+	filter := bson.M{"article_id": articleId}
+	opt := options.Find().
+		SetSort(bson.M{"version": -1}).
+		SetSkip((page - 1) * pageSize).
+		SetLimit(pageSize).
+		SetProjection(bson.M{"content": 0})
+	countCtx, end := StartSpan(ctx, RevisionColl, "count")
+	count, err = RevisionColl.CountDocuments(countCtx, filter)
+	end(err)
+	if err != nil {
+		return
+	}
+	findCtx, end := StartSpan(ctx, RevisionColl, "find")
+	defer func() { end(err) }()
+	query, err := RevisionColl.Find(findCtx, filter, opt)
+	if err != nil {
+		return
+	}
+	err = query.All(findCtx, &rs)
+	return
+}
+
+// Revision 文章某个版本的修订，不存在时返回 ErrRevisionNotFound
+func (a *Article) Revision(ctx context.Context, articleId string, version int64) (*pbs.Revision, error) {
+	rs := new(pbs.Revision)
+	ctx, end := StartSpan(ctx, RevisionColl, "findOne")
+	err := RevisionColl.FindOne(ctx, bson.M{"article_id": articleId, "version": version}).Decode(rs)
+	if err == mongo.ErrNoDocuments {
+		end(nil)
+		return nil, ErrRevisionNotFound
+	}
+	end(err)
+	return rs, err
+}
+
+// Rollback 将文章恢复为version版本的内容并保存为新版本，currentVersion为读取到的当前版本号，返回新的修订
+func (c *Article) Rollback(ctx context.Context, articleId string, version, currentVersion int64) (*pbs.Revision, error) {
+	target, err := c.Revision(ctx, articleId, version)
+	if err != nil {
+		return nil, err
+	}
+	set := bson.M{
+		"title":        target.Title,
+		"content":      target.Content,
+		"article_type": target.ArticleType,
+		"cover_img":    target.CoverImg,
+		"updated_at":   time.Now().Unix(),
+	}
+	rev := &pbs.Revision{Action: RevisionRollback, RollbackVersion: version}
+	if err := c.update(ctx, articleId, currentVersion, set, rev); err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+// fillRevision 用修改后的文章填充修订，prev不为nil时记录修改了哪些字段
+func fillRevision(ctx context.Context, rev *pbs.Revision, a, prev *pbs.Article) {
+	rev.Id = primitive.NewObjectID().Hex()
+	rev.ArticleId = a.Id
+	rev.Version = a.Version
+	rev.Author = utils.UserFromContext(ctx)
+	rev.CreatedAt = time.Now().Unix()
+	rev.Title, rev.Content, rev.ArticleType, rev.CoverImg = a.Title, a.Content, a.ArticleType, a.CoverImg
+	if prev != nil {
+		rev.Changed = changedFields(prev, a)
+	}
+}
+
+// baselineRevision 旧文章修改前的内容，时间取最后修改时间
+func baselineRevision(a *pbs.Article) *pbs.Revision {
+	rev := &pbs.Revision{
+		Id:          primitive.NewObjectID().Hex(),
+		ArticleId:   a.Id,
+		Version:     a.Version,
+		Action:      RevisionBaseline,
+		CreatedAt:   a.UpdatedAt,
+		Title:       a.Title,
+		Content:     a.Content,
+		ArticleType: a.ArticleType,
+		CoverImg:    a.CoverImg,
+	}
+	if rev.CreatedAt == 0 {
+		rev.CreatedAt = a.CreatedAt
+	}
+	return rev
+}
+
+// changedFields 两个版本之间内容不同的字段，使用JSON名称
+func changedFields(a, b *pbs.Article) []string {
+	var rs []string
+	if a.Title != b.Title {
+		rs = append(rs, "title")
+	}
+	if a.Content != b.Content {
+		rs = append(rs, "content")
+	}
+	if a.ArticleType != b.ArticleType {
+		rs = append(rs, "article_type")
+	}
+	if a.CoverImg != b.CoverImg {
+		rs = append(rs, "cover_img")
+	}
+	return rs
+}
+
+// saveRevision 保存修订
+func saveRevision(ctx context.Context, rev *pbs.Revision) error {
+	insertCtx, end := StartSpan(ctx, RevisionColl, "insert")
+	_, err := RevisionColl.InsertOne(insertCtx, rev)
+	end(err)
+	if err != nil {
+		return fmt.Errorf("save article revision %s@%d: %w", rev.ArticleId, rev.Version, err)
+	}
+	return nil
+}
+
+// transactions Mongo是否支持事务，启动时由 DetectTransactions 设置
+var transactions bool
+
+// insertRevision 保存修订，测试中替换
+var insertRevision = saveRevision
+
+// DetectTransactions 检查Mongo是否支持事务，只有副本集和分片集群支持，单机部署时文章的修订改为尽力保存
+func DetectTransactions(ctx context.Context) (bool, error) {
+	admin := config.Db.Client().Database("admin")
+	var hello bson.M
+	err := admin.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	if err != nil {
+		// 4.4.2 之前的版本没有hello命令
+		err = admin.RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&hello)
+	}
+	if err != nil {
+		return false, err
+	}
+	transactions = supportsTransactions(hello)
+	return transactions, nil
+}
+
+// supportsTransactions 根据hello的结果判断部署方式，副本集成员带setName，mongos返回 isdbgrid
+func supportsTransactions(hello bson.M) bool {
+	_, replicaSet := hello["setName"]
+	return replicaSet || hello["msg"] == "isdbgrid"
+}
+
+// writeArticle 执行write修改文章，并保存write返回的修订，write中的数据库操作须使用传入的ctx。
+// 支持事务时两者在同一事务中，修订保存失败时修改一起回滚，遇到临时错误时由驱动重试整个write；
+// 单机部署时修改成功后再保存修订，失败只记录日志
+func writeArticle(ctx context.Context, write func(ctx context.Context) ([]*pbs.Revision, error)) error {
+	if !transactions {
+		revs, err := write(ctx)
+		if err != nil {
+			return err
+		}
+		for _, rev := range revs {
+			if err = insertRevision(ctx, rev); err != nil {
+				utils.WithContext(ctx).WithError(err).WithFields(utils.Fields{
+					"article_id": rev.ArticleId,
+					"version":    rev.Version,
+				}).Error("save article revision err")
+			}
+		}
+		return nil
+	}
+	sess, err := config.Db.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer sess.EndSession(ctx)
+	_, err = sess.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		revs, err := write(sc)
+		if err != nil {
+			return nil, err
+		}
+		for _, rev := range revs {
+			if err = insertRevision(sc, rev); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// applySet 将$set应用到修改前的文章上，得到修改后的文章
+func applySet(before *pbs.Article, set bson.M) (*pbs.Article, error) {
+	raw, err := bson.Marshal(before)
+	if err != nil {
+		return nil, err
+	}
+	doc := bson.M{}
+	if err = bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	for k, v := range set {
+		doc[k] = v
+	}
+	doc["version"] = before.Version + 1
+	if raw, err = bson.Marshal(doc); err != nil {
+		return nil, err
+	}
+	after := new(pbs.Article)
+	return after, bson.Unmarshal(raw, after)
+}
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"playGround/pbs"
+	"playGround/utils"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestApplySet(t *testing.T) {
+	before := &pbs.Article{Id: "a1", Title: "old", Content: "body", CoverImg: "c.png", Version: 3, CreatedAt: 100}
+	set, err := FieldsByPath(&pbs.Article{Title: "new", ArticleType: pbs.ArticleType_ARTICLE_TYPE_NOTICE}, []string{"title", "article_type", "cover_img"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := applySet(before, set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &pbs.Article{Id: "a1", Title: "new", Content: "body", ArticleType: pbs.ArticleType_ARTICLE_TYPE_NOTICE, Version: 4, CreatedAt: 100}
+	if !reflect.DeepEqual(after, want) {
+		t.Fatalf("after = %+v", after)
+	}
+	if got := changedFields(before, after); !reflect.DeepEqual(got, []string{"title", "article_type", "cover_img"}) {
+		t.Fatalf("changed = %v", got)
+	}
+}
+
+func TestApplySetInvalid(t *testing.T) {
+	// 无法写回文章的值要返回错误，不能跳过修订
+	if _, err := applySet(&pbs.Article{Id: "a1"}, bson.M{"title": 1}); err == nil {
+		t.Fatal("want error")
+	}
+}
+
+func TestFillRevision(t *testing.T) {
+	ctx := utils.ContextWithUser(context.Background(), "alice")
+	prev := &pbs.Article{Id: "a1", Title: "old", Version: 1}
+	a := &pbs.Article{Id: "a1", Title: "new", Version: 2}
+	rev := &pbs.Revision{Action: RevisionUpdate}
+	fillRevision(ctx, rev, a, prev)
+	if rev.ArticleId != "a1" || rev.Version != 2 || rev.Author != "alice" || rev.Title != "new" || !reflect.DeepEqual(rev.Changed, []string{"title"}) {
+		t.Fatalf("rev = %+v", rev)
+	}
+	base := baselineRevision(&pbs.Article{Id: "a1", CreatedAt: 100})
+	if base.Action != RevisionBaseline || base.Version != 0 || base.CreatedAt != 100 {
+		t.Fatalf("baseline = %+v", base)
+	}
+}
+
+func TestSupportsTransactions(t *testing.T) {
+	tests := []struct {
+		name  string
+		hello bson.M
+		want  bool
+	}{
+		{"standalone", bson.M{"isWritablePrimary": true}, false},
+		{"replica set", bson.M{"isWritablePrimary": true, "setName": "rs0"}, true},
+		{"mongos", bson.M{"msg": "isdbgrid"}, true},
+	}
+	for _, tt := range tests {
+		if got := supportsTransactions(tt.hello); got != tt.want {
+			t.Errorf("%s: supportsTransactions = %v", tt.name, got)
+		}
+	}
+}
+
+func TestWriteArticleStandalone(t *testing.T) {
+	saved := insertRevision
+	defer func() { insertRevision = saved }()
+	transactions = false
+
+	var inserted []int64
+	insertRevision = func(ctx context.Context, rev *pbs.Revision) error {
+		inserted = append(inserted, rev.Version)
+		return errors.New("revision collection unavailable")
+	}
+	// 单机部署时修订保存失败不影响文章修改
+	calls := 0
+	err := writeArticle(context.Background(), func(ctx context.Context) ([]*pbs.Revision, error) {
+		calls++
+		return []*pbs.Revision{{ArticleId: "a1", Version: 1}, {ArticleId: "a1", Version: 2}}, nil
+	})
+	if err != nil || calls != 1 || !reflect.DeepEqual(inserted, []int64{1, 2}) {
+		t.Fatalf("err = %v, calls = %d, inserted = %v", err, calls, inserted)
+	}
+
+	// 修改失败时不保存修订
+	inserted = nil
+	werr := errors.New("write failed")
+	err = writeArticle(context.Background(), func(ctx context.Context) ([]*pbs.Revision, error) {
+		return nil, werr
+	})
+	if err != werr || inserted != nil {
+		t.Fatalf("err = %v, inserted = %v", err, inserted)
+	}
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"playGround/config"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Upload 上传记录，文件内容保存在 Blob 中
+type Upload struct {
+	Id          string `json:"id" bson:"_id"`
+	BlobId      string `json:"blob_id" bson:"blob_id"`                 //引用的文件ID
+	Key         string `json:"key" bson:"key"`                         //存储中的key
+	Index       string `json:"index,omitempty" bson:"index,omitempty"` //压缩包中入口页面的相对路径
+	FileName    string `json:"file_name" bson:"file_name"`             //原始文件名
+	Name        string `json:"name" bson:"name"`                       //上传类别
+	Uploader    string `json:"uploader" bson:"uploader"`               //上传人
+	Size        int64  `json:"size" bson:"size"`
+	ContentType string `json:"content_type" bson:"content_type"`
+	CreatedAt   int64  `json:"created_at" bson:"created_at"`
+}
+
+// UploadFilter 上传记录查询条件，字段为空时不过滤
+type UploadFilter struct {
+	Name        string //上传类别
+	Uploader    string //上传人
+	Keyword     string //文件名关键字
+	ContentType string //文件类型前缀，如 image/
+	Start       int64  //创建时间起
+	End         int64  //创建时间止
+}
+
+var UploadColl *mongo.Collection //集合
+
+func init() {
+	UploadColl = config.Db.Collection("upload")
+}
+
+func (u *Upload) Create(data *Upload) error {
+	if data.Id == "" {
+		data.Id = primitive.NewObjectID().Hex()
+	}
+	data.CreatedAt = time.Now().Unix()
+	_, err := UploadColl.InsertOne(Context, data)
+	return err
+}
+
+func (u *Upload) GetUploadList(where UploadFilter, page, pageSize int64) (rs []*Upload, count int64, err error) {
+	var filter = bson.M{}
+	if where.Name != "" {
+		filter["name"] = where.Name
+	}
+	if where.Uploader != "" {
+		filter["uploader"] = where.Uploader
+	}
+	if where.Keyword != "" {
+		filter["file_name"] = primitive.Regex{Pattern: regexp.QuoteMeta(where.Keyword), Options: "i"}
+	}
+	if where.ContentType != "" {
+		filter["content_type"] = primitive.Regex{Pattern: "^" + regexp.QuoteMeta(where.ContentType)}
+	}
+	if where.Start > 0 || where.End > 0 {
+		createdAt := bson.M{}
+		if where.Start > 0 {
+			createdAt["$gte"] = where.Start
+		}
+		if where.End > 0 {
+			createdAt["$lte"] = where.End
+		}
+		filter["created_at"] = createdAt
+	}
+	opt := &options.FindOptions{}
+	if page != -1 && page > 0 { //page等于-1时不分页
+		var offset = (page - 1) * pageSize
+		opt.SetLimit(pageSize)
+		opt.SetSkip(offset)
+	}
+	opt.SetSort(bson.M{"_id": -1})
+	if count, err = UploadColl.CountDocuments(Context, filter); err != nil {
+		return
+	}
+	query, err := UploadColl.Find(Context, filter, opt)
+	if err != nil {
+		return
+	}
+	rs = []*Upload{}
+	err = query.All(Context, &rs)
+	return
+}
+
+func (u *Upload) View(uploadId string) (rs *Upload, err error) {
+	rs = new(Upload)
+	err = UploadColl.FindOne(Context, bson.M{"_id": uploadId}).Decode(rs)
+	return
+}
+
+// Delete 删除上传记录，返回被删除的记录，记录不存在时返回 mongo.ErrNoDocuments
+func (u *Upload) Delete(uploadId string) (rs *Upload, err error) {
+	rs = new(Upload)
+	err = UploadColl.FindOneAndDelete(Context, bson.M{"_id": uploadId}).Decode(rs)
+	return
+}
@@ -7,6 +7,7 @@ import (
 	context "context"
 	fmt "fmt"
 	gogoproto "github.com/gogo/protobuf/gogoproto"
+	types "github.com/gogo/protobuf/types"
 	proto "github.com/golang/protobuf/proto"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
@@ -179,6 +180,38 @@ var E_Stdduration = gogoproto.E_Stdduration
 
 var E_Wktpointer = gogoproto.E_Wktpointer
 
+// 文章类型，Mongo中按数字保存。原来的 NEWS=0、AWARD=1、NOTICE=2 在启动时由 model.MigrateArticleTypes 统一加1
+type ArticleType int32
+
+const (
+	ArticleType_ARTICLE_TYPE_UNSPECIFIED ArticleType = 0
+	ArticleType_ARTICLE_TYPE_NEWS        ArticleType = 1
+	ArticleType_ARTICLE_TYPE_AWARD       ArticleType = 2
+	ArticleType_ARTICLE_TYPE_NOTICE      ArticleType = 3
+)
+
+var ArticleType_name = map[int32]string{
+	0: "ARTICLE_TYPE_UNSPECIFIED",
+	1: "ARTICLE_TYPE_NEWS",
+	2: "ARTICLE_TYPE_AWARD",
+	3: "ARTICLE_TYPE_NOTICE",
+}
+
+var ArticleType_value = map[string]int32{
+	"ARTICLE_TYPE_UNSPECIFIED": 0,
+	"ARTICLE_TYPE_NEWS":        1,
+	"ARTICLE_TYPE_AWARD":       2,
+	"ARTICLE_TYPE_NOTICE":      3,
+}
+
+func (x ArticleType) String() string {
+	return proto.EnumName(ArticleType_name, int32(x))
+}
+
+func (ArticleType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_5c593d380f9840a2, []int{0}
+}
+
 type ArticleId struct {
 	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 }
@@ -313,8 +346,9 @@ var xxx_messageInfo_Empty proto.InternalMessageInfo
 
 // 分页通用参数
 type PageParam struct {
-	Page     int64 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
-	PageSize int64 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Page     int64         `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize int64         `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Types    []ArticleType `protobuf:"varint,3,rep,packed,name=types,proto3,enum=pbs.ArticleType" json:"types,omitempty"`
 }
 
 func (m *PageParam) Reset()         { *m = PageParam{} }
@@ -364,15 +398,24 @@ func (m *PageParam) GetPageSize() int64 {
 	return 0
 }
 
+func (m *PageParam) GetTypes() []ArticleType {
+	if m != nil {
+		return m.Types
+	}
+	return nil
+}
+
 type Article struct {
-	Id          string `protobuf:"bytes,1,opt,name=id,proto3" json:"id" bson:"_id,omitempty"`
-	Title       string `protobuf:"bytes,2,opt,name=title,proto3" json:"title" bson:"title"`
-	Content     string `protobuf:"bytes,3,opt,name=content,proto3" json:"content" bson:"content"`
-	ArticleType uint32 `protobuf:"varint,4,opt,name=article_type,json=articleType,proto3" json:"article_type" bson:"article_type"`
-	CreatedAt   int64  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at" bson:"created_at"`
-	UpdatedAt   int64  `protobuf:"varint,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at" bson:"updated_at"`
-	DeletedAt   int64  `protobuf:"varint,8,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at" bson:"deleted_at"`
-	CoverImg    string `protobuf:"bytes,9,opt,name=cover_img,json=coverImg,proto3" json:"cover_img" bson:"cover_img"`
+	Id          string           `protobuf:"bytes,1,opt,name=id,proto3" json:"id" bson:"_id,omitempty"`
+	Title       string           `protobuf:"bytes,2,opt,name=title,proto3" json:"title" bson:"title"`
+	Content     string           `protobuf:"bytes,3,opt,name=content,proto3" json:"content" bson:"content"`
+	ArticleType ArticleType      `protobuf:"varint,4,opt,name=article_type,json=articleType,proto3,enum=pbs.ArticleType" json:"article_type" bson:"article_type"`
+	CreatedAt   int64            `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at" bson:"created_at"`
+	UpdatedAt   int64            `protobuf:"varint,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at" bson:"updated_at"`
+	DeletedAt   int64            `protobuf:"varint,8,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at" bson:"deleted_at"`
+	CoverImg    string           `protobuf:"bytes,9,opt,name=cover_img,json=coverImg,proto3" json:"cover_img" bson:"cover_img"`
+	Version     int64            `protobuf:"varint,11,opt,name=version,proto3" json:"version" bson:"version"`
+	UpdateMask  *types.FieldMask `protobuf:"bytes,10,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty" bson:"-"`
 }
 
 func (m *Article) Reset()         { *m = Article{} }
@@ -429,11 +472,11 @@ func (m *Article) GetContent() string {
 	return ""
 }
 
-func (m *Article) GetArticleType() uint32 {
+func (m *Article) GetArticleType() ArticleType {
 	if m != nil {
 		return m.ArticleType
 	}
-	return 0
+	return ArticleType_ARTICLE_TYPE_UNSPECIFIED
 }
 
 func (m *Article) GetCreatedAt() int64 {
@@ -464,588 +507,3157 @@ func (m *Article) GetCoverImg() string {
 	return ""
 }
 
-func init() {
-	proto.RegisterType((*ArticleId)(nil), "pbs.ArticleId")
-	proto.RegisterType((*Articles)(nil), "pbs.Articles")
-	proto.RegisterType((*Empty)(nil), "pbs.Empty")
-	proto.RegisterType((*PageParam)(nil), "pbs.PageParam")
-	proto.RegisterType((*Article)(nil), "pbs.Article")
+func (m *Article) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
 }
 
-func init() { proto.RegisterFile("article.proto", fileDescriptor_5c593d380f9840a2) }
+func (m *Article) GetUpdateMask() *types.FieldMask {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
 
-var fileDescriptor_5c593d380f9840a2 = []byte{
-	// 554 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x93, 0x3d, 0x8f, 0xd3, 0x30,
-	0x18, 0xc7, 0x9b, 0xa6, 0x6f, 0xf1, 0xb5, 0x15, 0x98, 0x1b, 0x42, 0x0f, 0xe2, 0x62, 0xde, 0x3a,
-	0x40, 0x2b, 0x1d, 0x03, 0x12, 0x42, 0x48, 0x0d, 0x30, 0x1c, 0x62, 0xa8, 0x7c, 0x30, 0x57, 0x79,
-	0x31, 0xc1, 0x52, 0xd3, 0x44, 0x89, 0x7b, 0x52, 0xef, 0x53, 0x30, 0xf2, 0x91, 0x58, 0x90, 0x6e,
-	0x64, 0xb2, 0x50, 0xbb, 0x65, 0xcc, 0xca, 0x82, 0xe2, 0xb8, 0x4d, 0x2b, 0x24, 0xa6, 0xf8, 0xff,
-	0x7b, 0xfc, 0xfc, 0x1c, 0x3b, 0x0e, 0xe8, 0x39, 0x09, 0x67, 0xde, 0x82, 0x8e, 0xe3, 0x24, 0xe2,
-	0x11, 0xd4, 0x63, 0x37, 0x1d, 0x3c, 0x0f, 0x18, 0xff, 0xba, 0x72, 0xc7, 0x5e, 0x14, 0x4e, 0x82,
-	0x28, 0x88, 0x26, 0xb2, 0xe6, 0xae, 0xbe, 0xc8, 0x24, 0x83, 0x1c, 0x95, 0x3d, 0xf8, 0x0c, 0x18,
-	0xd3, 0x52, 0x72, 0xe1, 0xc3, 0x3e, 0xa8, 0x33, 0xdf, 0xd4, 0x86, 0xda, 0xc8, 0x20, 0x75, 0xe6,
-	0x63, 0x1b, 0x74, 0x54, 0x31, 0x85, 0x43, 0xd0, 0xf0, 0x1d, 0xee, 0x98, 0xda, 0x50, 0x1f, 0x9d,
-	0x9c, 0x77, 0xc7, 0xb1, 0x9b, 0x8e, 0x55, 0x91, 0xc8, 0x0a, 0x3c, 0x05, 0x4d, 0x2f, 0x5a, 0x2d,
-	0xb9, 0x59, 0x1f, 0x6a, 0x23, 0x9d, 0x94, 0x01, 0xb7, 0x41, 0xf3, 0x7d, 0x18, 0xf3, 0x35, 0x7e,
-	0x0d, 0x8c, 0x99, 0x13, 0xd0, 0x99, 0x93, 0x38, 0x21, 0x84, 0xa0, 0x11, 0x3b, 0x01, 0x95, 0x6b,
-	0xe9, 0x44, 0x8e, 0xe1, 0x19, 0x30, 0x8a, 0xe7, 0x3c, 0x65, 0xd7, 0x54, 0x39, 0x3a, 0x05, 0xb8,
-	0x64, 0xd7, 0x14, 0xff, 0xd1, 0x41, 0x5b, 0x2d, 0x07, 0xc7, 0xd5, 0x6b, 0xda, 0x56, 0x26, 0x50,
-	0x9d, 0xf9, 0xb9, 0x40, 0xa7, 0x6e, 0x1a, 0x2d, 0x5f, 0xe1, 0x39, 0xf3, 0x9f, 0x45, 0x21, 0xe3,
-	0x54, 0x2e, 0x5a, 0x6c, 0x03, 0x4e, 0x40, 0x93, 0x33, 0xbe, 0x28, 0xa5, 0x86, 0x7d, 0x37, 0x13,
-	0xa8, 0x04, 0xb9, 0x40, 0xdd, 0xb2, 0x4b, 0x46, 0x4c, 0x4a, 0x0c, 0x5f, 0x82, 0xb6, 0x17, 0x2d,
-	0x39, 0x5d, 0x72, 0x53, 0x97, 0x2d, 0xf7, 0x33, 0x81, 0x76, 0x28, 0x17, 0xa8, 0x5f, 0x36, 0x29,
-	0x80, 0xc9, 0xae, 0x04, 0x3f, 0x80, 0xae, 0xfa, 0x24, 0x73, 0xbe, 0x8e, 0xa9, 0xd9, 0x18, 0x6a,
-	0xa3, 0x9e, 0xfd, 0x34, 0x13, 0xe8, 0x88, 0xe7, 0x02, 0xdd, 0x29, 0x15, 0x87, 0x14, 0x93, 0x13,
-	0x15, 0x3f, 0xad, 0x63, 0x0a, 0x6d, 0x00, 0xbc, 0x84, 0x3a, 0x9c, 0xfa, 0x73, 0x87, 0x9b, 0xad,
-	0xe2, 0x3c, 0xec, 0x87, 0x99, 0x40, 0x07, 0x34, 0x17, 0xe8, 0xb6, 0x7a, 0x95, 0x3d, 0xc3, 0xc4,
-	0x50, 0x61, 0xca, 0x0b, 0xc7, 0x2a, 0xf6, 0x77, 0x8e, 0x76, 0xe5, 0xa8, 0x68, 0xe5, 0xa8, 0x18,
-	0x26, 0x86, 0x0a, 0xa5, 0xc3, 0xa7, 0x0b, 0xaa, 0x1c, 0x9d, 0xca, 0x51, 0xd1, 0xca, 0x51, 0x31,
-	0x4c, 0x0c, 0x15, 0xa6, 0x1c, 0xbe, 0x01, 0x86, 0x17, 0x5d, 0xd1, 0x64, 0xce, 0xc2, 0xc0, 0x34,
-	0xe4, 0x91, 0x3e, 0xc8, 0x04, 0xaa, 0x60, 0x2e, 0xd0, 0xad, 0xdd, 0xa1, 0x2a, 0x84, 0x49, 0x47,
-	0x8e, 0x2f, 0xc2, 0xe0, 0xfc, 0xa7, 0x06, 0xfa, 0xea, 0xeb, 0x5f, 0xd2, 0xe4, 0x8a, 0x79, 0x14,
-	0x62, 0xd0, 0x7a, 0x2b, 0xf7, 0x09, 0x8f, 0xee, 0xe2, 0x00, 0xc8, 0x24, 0xaf, 0x1c, 0x7c, 0x0c,
-	0x1a, 0x1f, 0x59, 0xca, 0x61, 0x5f, 0xb2, 0xfd, 0xed, 0x1b, 0xf4, 0x0e, 0x3b, 0xd2, 0x42, 0xf5,
-	0x59, 0x6e, 0xf7, 0x3f, 0xaa, 0x47, 0xa0, 0xf5, 0x4e, 0x6e, 0x47, 0xc9, 0xf6, 0x3f, 0xcd, 0xd1,
-	0xac, 0x27, 0xc5, 0x2c, 0xee, 0xb0, 0xc5, 0x3f, 0xb3, 0x8e, 0xcc, 0xf6, 0xbd, 0x1f, 0x1b, 0x4b,
-	0xbb, 0xd9, 0x58, 0xda, 0xef, 0x8d, 0xa5, 0x7d, 0xdb, 0x5a, 0xb5, 0xef, 0x5b, 0xab, 0x76, 0xb3,
-	0xb5, 0x6a, 0xbf, 0xb6, 0x56, 0x6d, 0x56, 0x73, 0x5b, 0xf2, 0xe7, 0x7c, 0xf1, 0x37, 0x00, 0x00,
-	0xff, 0xff, 0xa0, 0xf6, 0xf5, 0x48, 0xe1, 0x03, 0x00, 0x00,
+// 文章的修订，每次新建、修改、回滚后保存一份快照
+type Revision struct {
+	Id              string      `protobuf:"bytes,1,opt,name=id,proto3" json:"id" bson:"_id,omitempty"`
+	ArticleId       string      `protobuf:"bytes,2,opt,name=article_id,json=articleId,proto3" json:"article_id" bson:"article_id"`
+	Version         int64       `protobuf:"varint,3,opt,name=version,proto3" json:"version" bson:"version"`
+	Action          string      `protobuf:"bytes,4,opt,name=action,proto3" json:"action" bson:"action"`
+	Author          string      `protobuf:"bytes,5,opt,name=author,proto3" json:"author" bson:"author"`
+	CreatedAt       int64       `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at" bson:"created_at"`
+	RollbackVersion int64       `protobuf:"varint,7,opt,name=rollback_version,json=rollbackVersion,proto3" json:"rollback_version,omitempty" bson:"rollback_version,omitempty"`
+	Changed         []string    `protobuf:"bytes,8,rep,name=changed,proto3" json:"changed,omitempty" bson:"changed,omitempty"`
+	Title           string      `protobuf:"bytes,9,opt,name=title,proto3" json:"title" bson:"title"`
+	Content         string      `protobuf:"bytes,10,opt,name=content,proto3" json:"content,omitempty" bson:"content"`
+	ArticleType     ArticleType `protobuf:"varint,11,opt,name=article_type,json=articleType,proto3,enum=pbs.ArticleType" json:"article_type" bson:"article_type"`
+	CoverImg        string      `protobuf:"bytes,12,opt,name=cover_img,json=coverImg,proto3" json:"cover_img" bson:"cover_img"`
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *Revision) Reset()         { *m = Revision{} }
+func (m *Revision) String() string { return proto.CompactTextString(m) }
+func (*Revision) ProtoMessage()    {}
+func (*Revision) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5c593d380f9840a2, []int{5}
+}
+func (m *Revision) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Revision) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Revision.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Revision) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Revision.Merge(m, src)
+}
+func (m *Revision) XXX_Size() int {
+	return m.Size()
+}
+func (m *Revision) XXX_DiscardUnknown() {
+	xxx_messageInfo_Revision.DiscardUnknown(m)
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+var xxx_messageInfo_Revision proto.InternalMessageInfo
 
-// ArticleServiceClient is the client API for ArticleService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type ArticleServiceClient interface {
-	Create(ctx context.Context, in *Article, opts ...grpc.CallOption) (*Empty, error)
-	List(ctx context.Context, in *PageParam, opts ...grpc.CallOption) (*Articles, error)
-	Update(ctx context.Context, in *Article, opts ...grpc.CallOption) (*Empty, error)
-	Delete(ctx context.Context, in *ArticleId, opts ...grpc.CallOption) (*Empty, error)
-	Detail(ctx context.Context, in *ArticleId, opts ...grpc.CallOption) (*Article, error)
+func (m *Revision) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
 }
 
-type articleServiceClient struct {
-	cc *grpc.ClientConn
+func (m *Revision) GetArticleId() string {
+	if m != nil {
+		return m.ArticleId
+	}
+	return ""
 }
 
-func NewArticleServiceClient(cc *grpc.ClientConn) ArticleServiceClient {
-	return &articleServiceClient{cc}
+func (m *Revision) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
 }
 
-func (c *articleServiceClient) Create(ctx context.Context, in *Article, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
-	err := c.cc.Invoke(ctx, "/pbs.ArticleService/Create", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *Revision) GetAction() string {
+	if m != nil {
+		return m.Action
 	}
-	return out, nil
+	return ""
 }
 
-func (c *articleServiceClient) List(ctx context.Context, in *PageParam, opts ...grpc.CallOption) (*Articles, error) {
-	out := new(Articles)
-	err := c.cc.Invoke(ctx, "/pbs.ArticleService/List", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *Revision) GetAuthor() string {
+	if m != nil {
+		return m.Author
 	}
-	return out, nil
+	return ""
 }
 
-func (c *articleServiceClient) Update(ctx context.Context, in *Article, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
-	err := c.cc.Invoke(ctx, "/pbs.ArticleService/Update", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *Revision) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
 	}
-	return out, nil
+	return 0
 }
 
-func (c *articleServiceClient) Delete(ctx context.Context, in *ArticleId, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
-	err := c.cc.Invoke(ctx, "/pbs.ArticleService/Delete", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *Revision) GetRollbackVersion() int64 {
+	if m != nil {
+		return m.RollbackVersion
 	}
-	return out, nil
+	return 0
 }
 
-func (c *articleServiceClient) Detail(ctx context.Context, in *ArticleId, opts ...grpc.CallOption) (*Article, error) {
-	out := new(Article)
-	err := c.cc.Invoke(ctx, "/pbs.ArticleService/Detail", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *Revision) GetChanged() []string {
+	if m != nil {
+		return m.Changed
 	}
-	return out, nil
+	return nil
 }
 
-// ArticleServiceServer is the server API for ArticleService service.
-type ArticleServiceServer interface {
-	Create(context.Context, *Article) (*Empty, error)
-	List(context.Context, *PageParam) (*Articles, error)
-	Update(context.Context, *Article) (*Empty, error)
-	Delete(context.Context, *ArticleId) (*Empty, error)
-	Detail(context.Context, *ArticleId) (*Article, error)
+func (m *Revision) GetTitle() string {
+	if m != nil {
+		return m.Title
+	}
+	return ""
 }
 
-// UnimplementedArticleServiceServer can be embedded to have forward compatible implementations.
-type UnimplementedArticleServiceServer struct {
+func (m *Revision) GetContent() string {
+	if m != nil {
+		return m.Content
+	}
+	return ""
 }
 
-func (*UnimplementedArticleServiceServer) Create(ctx context.Context, req *Article) (*Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+func (m *Revision) GetArticleType() ArticleType {
+	if m != nil {
+		return m.ArticleType
+	}
+	return ArticleType_ARTICLE_TYPE_UNSPECIFIED
 }
-func (*UnimplementedArticleServiceServer) List(ctx context.Context, req *PageParam) (*Articles, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+
+func (m *Revision) GetCoverImg() string {
+	if m != nil {
+		return m.CoverImg
+	}
+	return ""
 }
-func (*UnimplementedArticleServiceServer) Update(ctx context.Context, req *Article) (*Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+
+type Revisions struct {
+	Data  []*Revision `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+	Count int64       `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
 }
-func (*UnimplementedArticleServiceServer) Delete(ctx context.Context, req *ArticleId) (*Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+
+func (m *Revisions) Reset()         { *m = Revisions{} }
+func (m *Revisions) String() string { return proto.CompactTextString(m) }
+func (*Revisions) ProtoMessage()    {}
+func (*Revisions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5c593d380f9840a2, []int{6}
 }
-func (*UnimplementedArticleServiceServer) Detail(ctx context.Context, req *ArticleId) (*Article, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Detail not implemented")
+func (m *Revisions) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
 }
-
-func RegisterArticleServiceServer(s *grpc.Server, srv ArticleServiceServer) {
-	s.RegisterService(&_ArticleService_serviceDesc, srv)
+func (m *Revisions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Revisions.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Revisions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Revisions.Merge(m, src)
+}
+func (m *Revisions) XXX_Size() int {
+	return m.Size()
+}
+func (m *Revisions) XXX_DiscardUnknown() {
+	xxx_messageInfo_Revisions.DiscardUnknown(m)
 }
 
-func _ArticleService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Article)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(ArticleServiceServer).Create(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/pbs.ArticleService/Create",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ArticleServiceServer).Create(ctx, req.(*Article))
+var xxx_messageInfo_Revisions proto.InternalMessageInfo
+
+func (m *Revisions) GetData() []*Revision {
+	if m != nil {
+		return m.Data
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-func _ArticleService_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PageParam)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(ArticleServiceServer).List(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/pbs.ArticleService/List",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ArticleServiceServer).List(ctx, req.(*PageParam))
+func (m *Revisions) GetCount() int64 {
+	if m != nil {
+		return m.Count
 	}
-	return interceptor(ctx, in, info, handler)
+	return 0
 }
 
-func _ArticleService_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Article)
-	if err := dec(in); err != nil {
-		return nil, err
+type RevisionParam struct {
+	ArticleId string `protobuf:"bytes,1,opt,name=article_id,json=articleId,proto3" json:"article_id,omitempty"`
+	Page      int64  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize  int64  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+}
+
+func (m *RevisionParam) Reset()         { *m = RevisionParam{} }
+func (m *RevisionParam) String() string { return proto.CompactTextString(m) }
+func (*RevisionParam) ProtoMessage()    {}
+func (*RevisionParam) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5c593d380f9840a2, []int{7}
+}
+func (m *RevisionParam) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RevisionParam) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RevisionParam.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if interceptor == nil {
-		return srv.(ArticleServiceServer).Update(ctx, in)
+}
+func (m *RevisionParam) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevisionParam.Merge(m, src)
+}
+func (m *RevisionParam) XXX_Size() int {
+	return m.Size()
+}
+func (m *RevisionParam) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevisionParam.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevisionParam proto.InternalMessageInfo
+
+func (m *RevisionParam) GetArticleId() string {
+	if m != nil {
+		return m.ArticleId
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/pbs.ArticleService/Update",
+	return ""
+}
+
+func (m *RevisionParam) GetPage() int64 {
+	if m != nil {
+		return m.Page
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ArticleServiceServer).Update(ctx, req.(*Article))
+	return 0
+}
+
+func (m *RevisionParam) GetPageSize() int64 {
+	if m != nil {
+		return m.PageSize
 	}
-	return interceptor(ctx, in, info, handler)
+	return 0
 }
 
-func _ArticleService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ArticleId)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(ArticleServiceServer).Delete(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/pbs.ArticleService/Delete",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ArticleServiceServer).Delete(ctx, req.(*ArticleId))
-	}
-	return interceptor(ctx, in, info, handler)
+type RevisionId struct {
+	ArticleId string `protobuf:"bytes,1,opt,name=article_id,json=articleId,proto3" json:"article_id,omitempty"`
+	Version   int64  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
 }
 
-func _ArticleService_Detail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ArticleId)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(ArticleServiceServer).Detail(ctx, in)
+func (m *RevisionId) Reset()         { *m = RevisionId{} }
+func (m *RevisionId) String() string { return proto.CompactTextString(m) }
+func (*RevisionId) ProtoMessage()    {}
+func (*RevisionId) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5c593d380f9840a2, []int{8}
+}
+func (m *RevisionId) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RevisionId) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RevisionId.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/pbs.ArticleService/Detail",
+}
+func (m *RevisionId) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevisionId.Merge(m, src)
+}
+func (m *RevisionId) XXX_Size() int {
+	return m.Size()
+}
+func (m *RevisionId) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevisionId.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevisionId proto.InternalMessageInfo
+
+func (m *RevisionId) GetArticleId() string {
+	if m != nil {
+		return m.ArticleId
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ArticleServiceServer).Detail(ctx, req.(*ArticleId))
+	return ""
+}
+
+func (m *RevisionId) GetVersion() int64 {
+	if m != nil {
+		return m.Version
 	}
-	return interceptor(ctx, in, info, handler)
+	return 0
 }
 
-var _ArticleService_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "pbs.ArticleService",
-	HandlerType: (*ArticleServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Create",
-			Handler:    _ArticleService_Create_Handler,
-		},
-		{
-			MethodName: "List",
-			Handler:    _ArticleService_List_Handler,
-		},
-		{
-			MethodName: "Update",
-			Handler:    _ArticleService_Update_Handler,
-		},
-		{
-			MethodName: "Delete",
-			Handler:    _ArticleService_Delete_Handler,
-		},
-		{
-			MethodName: "Detail",
-			Handler:    _ArticleService_Detail_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "article.proto",
+type DiffParam struct {
+	ArticleId string `protobuf:"bytes,1,opt,name=article_id,json=articleId,proto3" json:"article_id,omitempty"`
+	From      int64  `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`
+	To        int64  `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`
 }
 
-func (m *ArticleId) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *DiffParam) Reset()         { *m = DiffParam{} }
+func (m *DiffParam) String() string { return proto.CompactTextString(m) }
+func (*DiffParam) ProtoMessage()    {}
+func (*DiffParam) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5c593d380f9840a2, []int{9}
+}
+func (m *DiffParam) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DiffParam) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DiffParam.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return dAtA[:n], nil
+}
+func (m *DiffParam) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiffParam.Merge(m, src)
+}
+func (m *DiffParam) XXX_Size() int {
+	return m.Size()
+}
+func (m *DiffParam) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiffParam.DiscardUnknown(m)
 }
 
-func (m *ArticleId) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+var xxx_messageInfo_DiffParam proto.InternalMessageInfo
+
+func (m *DiffParam) GetArticleId() string {
+	if m != nil {
+		return m.ArticleId
+	}
+	return ""
 }
 
-func (m *ArticleId) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Id) > 0 {
-		i -= len(m.Id)
-		copy(dAtA[i:], m.Id)
-		i = encodeVarintArticle(dAtA, i, uint64(len(m.Id)))
-		i--
-		dAtA[i] = 0xa
+func (m *DiffParam) GetFrom() int64 {
+	if m != nil {
+		return m.From
 	}
-	return len(dAtA) - i, nil
+	return 0
 }
 
-func (m *Articles) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *DiffParam) GetTo() int64 {
+	if m != nil {
+		return m.To
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *Articles) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+// 两个版本的差异，只包含有变化的字段
+type RevisionDiff struct {
+	From   int64        `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	To     int64        `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	Fields []*FieldDiff `protobuf:"bytes,3,rep,name=fields,proto3" json:"fields,omitempty"`
 }
 
-func (m *Articles) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Count != 0 {
-		i = encodeVarintArticle(dAtA, i, uint64(m.Count))
-		i--
-		dAtA[i] = 0x10
-	}
-	if len(m.Data) > 0 {
-		for iNdEx := len(m.Data) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Data[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintArticle(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
+func (m *RevisionDiff) Reset()         { *m = RevisionDiff{} }
+func (m *RevisionDiff) String() string { return proto.CompactTextString(m) }
+func (*RevisionDiff) ProtoMessage()    {}
+func (*RevisionDiff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5c593d380f9840a2, []int{10}
+}
+func (m *RevisionDiff) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RevisionDiff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RevisionDiff.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
 }
-
-func (m *Empty) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+func (m *RevisionDiff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevisionDiff.Merge(m, src)
+}
+func (m *RevisionDiff) XXX_Size() int {
+	return m.Size()
+}
+func (m *RevisionDiff) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevisionDiff.DiscardUnknown(m)
 }
 
-func (m *Empty) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+var xxx_messageInfo_RevisionDiff proto.InternalMessageInfo
+
+func (m *RevisionDiff) GetFrom() int64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
 }
 
-func (m *Empty) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	return len(dAtA) - i, nil
+func (m *RevisionDiff) GetTo() int64 {
+	if m != nil {
+		return m.To
+	}
+	return 0
 }
 
-func (m *PageParam) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *RevisionDiff) GetFields() []*FieldDiff {
+	if m != nil {
+		return m.Fields
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *PageParam) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type FieldDiff struct {
+	Field   string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Diff    string `protobuf:"bytes,2,opt,name=diff,proto3" json:"diff,omitempty"`
+	Added   int32  `protobuf:"varint,3,opt,name=added,proto3" json:"added,omitempty"`
+	Deleted int32  `protobuf:"varint,4,opt,name=deleted,proto3" json:"deleted,omitempty"`
 }
 
-func (m *PageParam) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.PageSize != 0 {
-		i = encodeVarintArticle(dAtA, i, uint64(m.PageSize))
-		i--
-		dAtA[i] = 0x10
+func (m *FieldDiff) Reset()         { *m = FieldDiff{} }
+func (m *FieldDiff) String() string { return proto.CompactTextString(m) }
+func (*FieldDiff) ProtoMessage()    {}
+func (*FieldDiff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5c593d380f9840a2, []int{11}
+}
+func (m *FieldDiff) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FieldDiff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_FieldDiff.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if m.Page != 0 {
-		i = encodeVarintArticle(dAtA, i, uint64(m.Page))
-		i--
-		dAtA[i] = 0x8
+}
+func (m *FieldDiff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FieldDiff.Merge(m, src)
+}
+func (m *FieldDiff) XXX_Size() int {
+	return m.Size()
+}
+func (m *FieldDiff) XXX_DiscardUnknown() {
+	xxx_messageInfo_FieldDiff.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FieldDiff proto.InternalMessageInfo
+
+func (m *FieldDiff) GetField() string {
+	if m != nil {
+		return m.Field
 	}
-	return len(dAtA) - i, nil
+	return ""
 }
 
-func (m *Article) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *FieldDiff) GetDiff() string {
+	if m != nil {
+		return m.Diff
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *Article) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *FieldDiff) GetAdded() int32 {
+	if m != nil {
+		return m.Added
+	}
+	return 0
 }
 
-func (m *Article) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.CoverImg) > 0 {
-		i -= len(m.CoverImg)
-		copy(dAtA[i:], m.CoverImg)
-		i = encodeVarintArticle(dAtA, i, uint64(len(m.CoverImg)))
-		i--
-		dAtA[i] = 0x4a
+func (m *FieldDiff) GetDeleted() int32 {
+	if m != nil {
+		return m.Deleted
 	}
-	if m.DeletedAt != 0 {
-		i = encodeVarintArticle(dAtA, i, uint64(m.DeletedAt))
-		i--
-		dAtA[i] = 0x40
+	return 0
+}
+
+type RollbackParam struct {
+	ArticleId      string `protobuf:"bytes,1,opt,name=article_id,json=articleId,proto3" json:"article_id,omitempty"`
+	Version        int64  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	CurrentVersion int64  `protobuf:"varint,3,opt,name=current_version,json=currentVersion,proto3" json:"current_version,omitempty"`
+}
+
+func (m *RollbackParam) Reset()         { *m = RollbackParam{} }
+func (m *RollbackParam) String() string { return proto.CompactTextString(m) }
+func (*RollbackParam) ProtoMessage()    {}
+func (*RollbackParam) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5c593d380f9840a2, []int{12}
+}
+func (m *RollbackParam) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RollbackParam) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RollbackParam.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if m.UpdatedAt != 0 {
-		i = encodeVarintArticle(dAtA, i, uint64(m.UpdatedAt))
-		i--
-		dAtA[i] = 0x38
+}
+func (m *RollbackParam) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RollbackParam.Merge(m, src)
+}
+func (m *RollbackParam) XXX_Size() int {
+	return m.Size()
+}
+func (m *RollbackParam) XXX_DiscardUnknown() {
+	xxx_messageInfo_RollbackParam.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RollbackParam proto.InternalMessageInfo
+
+func (m *RollbackParam) GetArticleId() string {
+	if m != nil {
+		return m.ArticleId
 	}
-	if m.CreatedAt != 0 {
-		i = encodeVarintArticle(dAtA, i, uint64(m.CreatedAt))
-		i--
-		dAtA[i] = 0x30
+	return ""
+}
+
+func (m *RollbackParam) GetVersion() int64 {
+	if m != nil {
+		return m.Version
 	}
-	if m.ArticleType != 0 {
-		i = encodeVarintArticle(dAtA, i, uint64(m.ArticleType))
-		i--
-		dAtA[i] = 0x20
+	return 0
+}
+
+func (m *RollbackParam) GetCurrentVersion() int64 {
+	if m != nil {
+		return m.CurrentVersion
 	}
-	if len(m.Content) > 0 {
-		i -= len(m.Content)
-		copy(dAtA[i:], m.Content)
-		i = encodeVarintArticle(dAtA, i, uint64(len(m.Content)))
-		i--
-		dAtA[i] = 0x1a
+	return 0
+}
+
+func init() {
+	proto.RegisterEnum("pbs.ArticleType", ArticleType_name, ArticleType_value)
+	proto.RegisterType((*ArticleId)(nil), "pbs.ArticleId")
+	proto.RegisterType((*Articles)(nil), "pbs.Articles")
+	proto.RegisterType((*Empty)(nil), "pbs.Empty")
+	proto.RegisterType((*PageParam)(nil), "pbs.PageParam")
+	proto.RegisterType((*Article)(nil), "pbs.Article")
+	proto.RegisterType((*Revision)(nil), "pbs.Revision")
+	proto.RegisterType((*Revisions)(nil), "pbs.Revisions")
+	proto.RegisterType((*RevisionParam)(nil), "pbs.RevisionParam")
+	proto.RegisterType((*RevisionId)(nil), "pbs.RevisionId")
+	proto.RegisterType((*DiffParam)(nil), "pbs.DiffParam")
+	proto.RegisterType((*RevisionDiff)(nil), "pbs.RevisionDiff")
+	proto.RegisterType((*FieldDiff)(nil), "pbs.FieldDiff")
+	proto.RegisterType((*RollbackParam)(nil), "pbs.RollbackParam")
+}
+
+func init() { proto.RegisterFile("article.proto", fileDescriptor_5c593d380f9840a2) }
+
+var fileDescriptor_5c593d380f9840a2 = []byte{
+	// 1172 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x4d, 0x6f, 0x1a, 0xc7,
+	0x1b, 0x07, 0x16, 0x30, 0xfb, 0x60, 0x08, 0x9e, 0x24, 0xff, 0xff, 0x96, 0x24, 0x2c, 0x99, 0x36,
+	0x31, 0xaa, 0x6a, 0xac, 0xe2, 0x43, 0xa5, 0x1e, 0x2a, 0x81, 0x4d, 0x2a, 0xd4, 0xd4, 0xb5, 0xc6,
+	0x4e, 0xa3, 0x56, 0x95, 0xd0, 0xc2, 0x0e, 0x78, 0x65, 0x60, 0xe9, 0xee, 0x60, 0xc9, 0xf9, 0x14,
+	0x3d, 0xf4, 0xd0, 0xcf, 0xd0, 0x73, 0x3f, 0x44, 0x8f, 0x39, 0xf6, 0xb4, 0xaa, 0xec, 0x1b, 0x47,
+	0x3e, 0x41, 0x35, 0x2f, 0xcb, 0xee, 0x3a, 0x75, 0x6a, 0x4b, 0x39, 0xed, 0x3e, 0xbf, 0xe7, 0x75,
+	0x9e, 0x79, 0x5e, 0x06, 0x4a, 0x96, 0xc7, 0x9c, 0xe1, 0x84, 0x36, 0xe7, 0x9e, 0xcb, 0x5c, 0xa4,
+	0xcd, 0x07, 0x7e, 0x75, 0x67, 0xec, 0xb0, 0xd3, 0xc5, 0xa0, 0x39, 0x74, 0xa7, 0xbb, 0x63, 0x77,
+	0xec, 0xee, 0x0a, 0xde, 0x60, 0x31, 0x12, 0x94, 0x20, 0xc4, 0x9f, 0xd4, 0xa9, 0xd6, 0xc7, 0xae,
+	0x3b, 0x9e, 0xd0, 0x48, 0x6a, 0xe4, 0xd0, 0x89, 0xdd, 0x9f, 0x5a, 0xfe, 0x99, 0x94, 0xc0, 0x8f,
+	0x40, 0x6f, 0x4b, 0x37, 0x3d, 0x1b, 0x95, 0x21, 0xe3, 0xd8, 0x46, 0xba, 0x9e, 0x6e, 0xe8, 0x24,
+	0xe3, 0xd8, 0xb8, 0x03, 0x05, 0xc5, 0xf4, 0x51, 0x1d, 0xb2, 0xb6, 0xc5, 0x2c, 0x23, 0x5d, 0xd7,
+	0x1a, 0xc5, 0xd6, 0x66, 0x73, 0x3e, 0xf0, 0x9b, 0x8a, 0x49, 0x04, 0x07, 0x3d, 0x80, 0xdc, 0xd0,
+	0x5d, 0xcc, 0x98, 0x91, 0xa9, 0xa7, 0x1b, 0x1a, 0x91, 0x04, 0xde, 0x80, 0x5c, 0x77, 0x3a, 0x67,
+	0x17, 0xd8, 0x06, 0xfd, 0xc8, 0x1a, 0xd3, 0x23, 0xcb, 0xb3, 0xa6, 0x08, 0x41, 0x76, 0x6e, 0x8d,
+	0xa9, 0xf0, 0xa5, 0x11, 0xf1, 0x8f, 0x1e, 0x81, 0xce, 0xbf, 0x7d, 0xdf, 0x79, 0x43, 0x95, 0x8d,
+	0x02, 0x07, 0x8e, 0x9d, 0x37, 0x14, 0x3d, 0x87, 0x1c, 0xbb, 0x98, 0x53, 0xdf, 0xd0, 0xea, 0x5a,
+	0xa3, 0xdc, 0xaa, 0xc4, 0xfd, 0x9f, 0x5c, 0xcc, 0x29, 0x91, 0x6c, 0xfc, 0x7b, 0x0e, 0x36, 0x14,
+	0x8c, 0x9a, 0xd1, 0x71, 0x3a, 0xb5, 0x65, 0x60, 0x66, 0x1c, 0x7b, 0x15, 0x98, 0x0f, 0x06, 0xbe,
+	0x3b, 0xfb, 0x12, 0xf7, 0x1d, 0xfb, 0x33, 0x77, 0xea, 0x30, 0x2a, 0x82, 0xe3, 0xc7, 0x45, 0xbb,
+	0x90, 0x63, 0x0e, 0x9b, 0x48, 0xe7, 0x7a, 0xe7, 0xa3, 0x65, 0x60, 0x4a, 0x60, 0x15, 0x98, 0x9b,
+	0x52, 0x4b, 0x90, 0x98, 0x48, 0x18, 0x7d, 0x01, 0x1b, 0x43, 0x77, 0xc6, 0xe8, 0x8c, 0x19, 0x9a,
+	0x50, 0x79, 0xb2, 0x0c, 0xcc, 0x10, 0x5a, 0x05, 0x66, 0x59, 0x2a, 0x29, 0x00, 0x93, 0x90, 0x85,
+	0x7e, 0x82, 0x4d, 0x75, 0xb9, 0x7d, 0x1e, 0xb6, 0x91, 0xad, 0xa7, 0xff, 0xed, 0x50, 0x9d, 0xed,
+	0x65, 0x60, 0x26, 0x24, 0x57, 0x81, 0x79, 0x5f, 0x1a, 0x8d, 0xa3, 0x98, 0x14, 0xad, 0x48, 0x0b,
+	0x75, 0x00, 0x86, 0x1e, 0xb5, 0x18, 0xb5, 0xfb, 0x16, 0x33, 0xf2, 0x3c, 0x93, 0x9d, 0x8f, 0x97,
+	0x81, 0x19, 0x43, 0x57, 0x81, 0xb9, 0xa5, 0x82, 0x5b, 0x63, 0x98, 0xe8, 0x8a, 0x68, 0x33, 0x6e,
+	0x63, 0x31, 0xb7, 0x43, 0x1b, 0x1b, 0x91, 0x8d, 0x08, 0x8d, 0x6c, 0x44, 0x18, 0x26, 0xba, 0x22,
+	0xa4, 0x0d, 0x9b, 0x4e, 0xa8, 0xb2, 0x51, 0x88, 0x6c, 0x44, 0x68, 0x64, 0x23, 0xc2, 0x30, 0xd1,
+	0x15, 0xd1, 0x66, 0xe8, 0x2b, 0xd0, 0x87, 0xee, 0x39, 0xf5, 0xfa, 0xce, 0x74, 0x6c, 0xe8, 0x22,
+	0xc9, 0x4f, 0x97, 0x81, 0x19, 0x81, 0xab, 0xc0, 0xac, 0x84, 0x69, 0x56, 0x10, 0x26, 0x05, 0xf1,
+	0xdf, 0x9b, 0x8e, 0xf9, 0x15, 0x9d, 0x53, 0xcf, 0x77, 0xdc, 0x99, 0x51, 0x14, 0x01, 0x88, 0x2b,
+	0x52, 0x50, 0x74, 0x45, 0x0a, 0xc0, 0x24, 0x64, 0xa1, 0x01, 0x14, 0xe5, 0x49, 0x44, 0xb7, 0x18,
+	0x50, 0x4f, 0x37, 0x8a, 0xad, 0x6a, 0x53, 0x36, 0x54, 0x33, 0x6c, 0xa8, 0xe6, 0x0b, 0xde, 0x50,
+	0xdf, 0x5a, 0xfe, 0x59, 0xe7, 0xd9, 0x32, 0x30, 0x1f, 0xc6, 0x54, 0xa2, 0xea, 0x5a, 0x05, 0x66,
+	0x41, 0xba, 0xd9, 0xc1, 0x44, 0x25, 0x90, 0xab, 0xe0, 0x3f, 0xf2, 0x50, 0x20, 0xf4, 0xdc, 0x11,
+	0x0e, 0xef, 0x5a, 0xad, 0x1d, 0x80, 0xb0, 0x06, 0x1c, 0x5b, 0x95, 0xac, 0xc8, 0x6e, 0x84, 0x46,
+	0xd9, 0x8d, 0x30, 0x4c, 0x74, 0x6b, 0xdd, 0xf0, 0xb1, 0xec, 0x68, 0x77, 0xca, 0xce, 0x1e, 0xe4,
+	0xad, 0x21, 0xe3, 0x7a, 0x59, 0xe1, 0xf8, 0xd1, 0x32, 0x30, 0x15, 0xb2, 0x0a, 0xcc, 0x92, 0x72,
+	0x2a, 0x68, 0x4c, 0x14, 0x43, 0x28, 0x2d, 0xd8, 0xa9, 0xeb, 0x19, 0xb9, 0x98, 0x92, 0x40, 0x62,
+	0x4a, 0x82, 0xe6, 0x4a, 0xe2, 0xe7, 0x83, 0x14, 0xf3, 0x0c, 0x2a, 0x9e, 0x3b, 0x99, 0x0c, 0xac,
+	0xe1, 0x59, 0x3f, 0x3c, 0xaf, 0x2c, 0xe9, 0xfd, 0x65, 0x60, 0x56, 0xaf, 0xf3, 0x12, 0x37, 0xf7,
+	0x54, 0x5a, 0xbe, 0x59, 0x06, 0x93, 0x7b, 0x21, 0xf3, 0x7b, 0x95, 0x9d, 0x6f, 0x60, 0x63, 0x78,
+	0x6a, 0xcd, 0xc6, 0xd4, 0x36, 0x0a, 0x75, 0xad, 0xa1, 0x77, 0x3e, 0x5f, 0x06, 0xe6, 0x96, 0x82,
+	0x12, 0xd6, 0x0d, 0x15, 0xf7, 0x75, 0x16, 0x9f, 0x15, 0x12, 0x8b, 0xa6, 0x92, 0x7e, 0xcb, 0xa9,
+	0xd4, 0x8e, 0xa6, 0x12, 0x08, 0x95, 0x6d, 0xe1, 0x5d, 0x42, 0x09, 0xef, 0xb7, 0x9e, 0x4f, 0xc5,
+	0x0f, 0x3a, 0x9f, 0x12, 0x3d, 0xbd, 0x79, 0xe7, 0x9e, 0xc6, 0x07, 0xa0, 0x87, 0x5d, 0xe3, 0xa3,
+	0xa7, 0x89, 0xbd, 0x54, 0x12, 0x21, 0x86, 0xdc, 0xf7, 0x2e, 0xa6, 0x3e, 0x94, 0x42, 0x39, 0xb9,
+	0x93, 0x9e, 0x24, 0x1a, 0x4a, 0x6e, 0xc1, 0x58, 0xaf, 0x84, 0x2b, 0x2b, 0x73, 0xd3, 0xca, 0xd2,
+	0x92, 0x2b, 0x0b, 0x77, 0x01, 0x42, 0x07, 0x3d, 0xfb, 0xbf, 0xac, 0x1b, 0x51, 0x27, 0x4a, 0x07,
+	0x21, 0x89, 0x0f, 0x41, 0x3f, 0x70, 0x46, 0xa3, 0xdb, 0xc6, 0x38, 0xf2, 0xdc, 0x69, 0x18, 0x23,
+	0xff, 0xe7, 0x4b, 0x9d, 0xb9, 0x2a, 0xb8, 0x0c, 0x73, 0xf1, 0x8f, 0xb0, 0x19, 0x86, 0xc5, 0xed,
+	0xae, 0x75, 0xd2, 0xef, 0xe8, 0x64, 0x42, 0x1d, 0xf4, 0x1c, 0xf2, 0xe2, 0xe5, 0x20, 0xd7, 0x6f,
+	0xb1, 0x55, 0x16, 0x69, 0x16, 0xb3, 0x8f, 0xdb, 0x20, 0x8a, 0x8b, 0x29, 0xe8, 0x6b, 0x90, 0xa7,
+	0x5d, 0xc0, 0x2a, 0x4c, 0x49, 0x70, 0x77, 0xb6, 0x33, 0x1a, 0xc9, 0x81, 0x45, 0xb2, 0xb6, 0x92,
+	0xb4, 0x6c, 0x9b, 0xda, 0x22, 0xca, 0x1c, 0x91, 0x04, 0x4f, 0x89, 0xda, 0x03, 0x62, 0xc8, 0xe4,
+	0x48, 0x48, 0xe2, 0x9f, 0xa1, 0x44, 0x54, 0xcb, 0xdd, 0x2a, 0x2d, 0x37, 0x26, 0x17, 0x6d, 0xc3,
+	0xbd, 0xe1, 0xc2, 0xf3, 0xe8, 0x8c, 0xf5, 0x13, 0x83, 0x90, 0x94, 0x15, 0xac, 0x5a, 0xfa, 0x53,
+	0x1f, 0x8a, 0xb1, 0xc2, 0x47, 0x8f, 0xc1, 0x68, 0x93, 0x93, 0xde, 0xfe, 0xcb, 0x6e, 0xff, 0xe4,
+	0x87, 0xa3, 0x6e, 0xff, 0xd5, 0xe1, 0xf1, 0x51, 0x77, 0xbf, 0xf7, 0xa2, 0xd7, 0x3d, 0xa8, 0xa4,
+	0xd0, 0x43, 0xd8, 0x4a, 0x70, 0x0f, 0xbb, 0xaf, 0x8f, 0x2b, 0x69, 0xf4, 0x3f, 0x40, 0x09, 0xb8,
+	0xfd, 0xba, 0x4d, 0x0e, 0x2a, 0x19, 0xf4, 0x7f, 0xb8, 0x9f, 0x14, 0xff, 0xee, 0xa4, 0xb7, 0xdf,
+	0xad, 0x68, 0xad, 0x5f, 0x35, 0x28, 0x2b, 0xaf, 0xc7, 0xd4, 0x3b, 0x77, 0x86, 0x14, 0x61, 0xc8,
+	0xef, 0x8b, 0xb9, 0x86, 0x12, 0x4f, 0xb0, 0x2a, 0x08, 0x4a, 0xbc, 0xb4, 0xd0, 0x33, 0xc8, 0xbe,
+	0x74, 0x7c, 0x86, 0xe4, 0x2d, 0xad, 0x1f, 0x5d, 0xd5, 0x52, 0x5c, 0xc3, 0xe7, 0xa6, 0x5e, 0xcd,
+	0xed, 0xf7, 0x9b, 0xfa, 0x04, 0xf2, 0x07, 0x22, 0xe9, 0xa8, 0x1c, 0x97, 0xe9, 0xd9, 0x09, 0xa9,
+	0xe7, 0x5c, 0x8a, 0x59, 0xce, 0xe4, 0x1d, 0xa9, 0x84, 0x65, 0xb4, 0x07, 0x25, 0x1e, 0x58, 0xd4,
+	0xbc, 0x28, 0xd1, 0xae, 0x32, 0xca, 0x72, 0x02, 0xf3, 0xd1, 0x0e, 0x14, 0xbf, 0xa6, 0x6b, 0x1d,
+	0x74, 0x2f, 0xc1, 0xee, 0xd9, 0xd5, 0x64, 0xcb, 0xa3, 0x16, 0x94, 0x44, 0x49, 0xae, 0xf5, 0xa5,
+	0xbd, 0x75, 0x0b, 0x55, 0xb7, 0x12, 0xf2, 0x1c, 0x47, 0x3b, 0x50, 0x08, 0xeb, 0x29, 0x0c, 0x29,
+	0x5e, 0x5e, 0xd7, 0x5c, 0x74, 0x1e, 0xff, 0x79, 0x59, 0x4b, 0xbf, 0xbd, 0xac, 0xa5, 0xff, 0xbe,
+	0xac, 0xa5, 0x7f, 0xb9, 0xaa, 0xa5, 0x7e, 0xbb, 0xaa, 0xa5, 0xde, 0x5e, 0xd5, 0x52, 0x7f, 0x5d,
+	0xd5, 0x52, 0x47, 0xa9, 0x41, 0x5e, 0xbc, 0x0e, 0xf6, 0xfe, 0x19, 0x00, 0x5d, 0xe7, 0xf1, 0x7b,
+	0xc1, 0x0b, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ArticleServiceClient is the client API for ArticleService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ArticleServiceClient interface {
+	Create(ctx context.Context, in *Article, opts ...grpc.CallOption) (*Empty, error)
+	List(ctx context.Context, in *PageParam, opts ...grpc.CallOption) (*Articles, error)
+	Update(ctx context.Context, in *Article, opts ...grpc.CallOption) (*Empty, error)
+	Delete(ctx context.Context, in *ArticleId, opts ...grpc.CallOption) (*Empty, error)
+	Detail(ctx context.Context, in *ArticleId, opts ...grpc.CallOption) (*Article, error)
+	ListRevisions(ctx context.Context, in *RevisionParam, opts ...grpc.CallOption) (*Revisions, error)
+	GetRevision(ctx context.Context, in *RevisionId, opts ...grpc.CallOption) (*Revision, error)
+	DiffRevisions(ctx context.Context, in *DiffParam, opts ...grpc.CallOption) (*RevisionDiff, error)
+	Rollback(ctx context.Context, in *RollbackParam, opts ...grpc.CallOption) (*Revision, error)
+}
+
+type articleServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewArticleServiceClient(cc *grpc.ClientConn) ArticleServiceClient {
+	return &articleServiceClient{cc}
+}
+
+func (c *articleServiceClient) Create(ctx context.Context, in *Article, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/pbs.ArticleService/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	if len(m.Title) > 0 {
-		i -= len(m.Title)
-		copy(dAtA[i:], m.Title)
-		i = encodeVarintArticle(dAtA, i, uint64(len(m.Title)))
-		i--
-		dAtA[i] = 0x12
+	return out, nil
+}
+
+func (c *articleServiceClient) List(ctx context.Context, in *PageParam, opts ...grpc.CallOption) (*Articles, error) {
+	out := new(Articles)
+	err := c.cc.Invoke(ctx, "/pbs.ArticleService/List", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	if len(m.Id) > 0 {
-		i -= len(m.Id)
-		copy(dAtA[i:], m.Id)
-		i = encodeVarintArticle(dAtA, i, uint64(len(m.Id)))
-		i--
-		dAtA[i] = 0xa
+	return out, nil
+}
+
+func (c *articleServiceClient) Update(ctx context.Context, in *Article, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/pbs.ArticleService/Update", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	return len(dAtA) - i, nil
+	return out, nil
 }
 
-func encodeVarintArticle(dAtA []byte, offset int, v uint64) int {
-	offset -= sovArticle(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (c *articleServiceClient) Delete(ctx context.Context, in *ArticleId, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/pbs.ArticleService/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return out, nil
 }
-func (m *ArticleId) Size() (n int) {
-	if m == nil {
-		return 0
+
+func (c *articleServiceClient) Detail(ctx context.Context, in *ArticleId, opts ...grpc.CallOption) (*Article, error) {
+	out := new(Article)
+	err := c.cc.Invoke(ctx, "/pbs.ArticleService/Detail", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	var l int
-	_ = l
-	l = len(m.Id)
-	if l > 0 {
-		n += 1 + l + sovArticle(uint64(l))
+	return out, nil
+}
+
+func (c *articleServiceClient) ListRevisions(ctx context.Context, in *RevisionParam, opts ...grpc.CallOption) (*Revisions, error) {
+	out := new(Revisions)
+	err := c.cc.Invoke(ctx, "/pbs.ArticleService/ListRevisions", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return out, nil
 }
 
-func (m *Articles) Size() (n int) {
-	if m == nil {
-		return 0
+func (c *articleServiceClient) GetRevision(ctx context.Context, in *RevisionId, opts ...grpc.CallOption) (*Revision, error) {
+	out := new(Revision)
+	err := c.cc.Invoke(ctx, "/pbs.ArticleService/GetRevision", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
-	var l int
-	_ = l
-	if len(m.Data) > 0 {
-		for _, e := range m.Data {
-			l = e.Size()
-			n += 1 + l + sovArticle(uint64(l))
+	return out, nil
+}
+
+func (c *articleServiceClient) DiffRevisions(ctx context.Context, in *DiffParam, opts ...grpc.CallOption) (*RevisionDiff, error) {
+	out := new(RevisionDiff)
+	err := c.cc.Invoke(ctx, "/pbs.ArticleService/DiffRevisions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *articleServiceClient) Rollback(ctx context.Context, in *RollbackParam, opts ...grpc.CallOption) (*Revision, error) {
+	out := new(Revision)
+	err := c.cc.Invoke(ctx, "/pbs.ArticleService/Rollback", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ArticleServiceServer is the server API for ArticleService service.
+type ArticleServiceServer interface {
+	Create(context.Context, *Article) (*Empty, error)
+	List(context.Context, *PageParam) (*Articles, error)
+	Update(context.Context, *Article) (*Empty, error)
+	Delete(context.Context, *ArticleId) (*Empty, error)
+	Detail(context.Context, *ArticleId) (*Article, error)
+	ListRevisions(context.Context, *RevisionParam) (*Revisions, error)
+	GetRevision(context.Context, *RevisionId) (*Revision, error)
+	DiffRevisions(context.Context, *DiffParam) (*RevisionDiff, error)
+	Rollback(context.Context, *RollbackParam) (*Revision, error)
+}
+
+// UnimplementedArticleServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedArticleServiceServer struct {
+}
+
+func (*UnimplementedArticleServiceServer) Create(ctx context.Context, req *Article) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (*UnimplementedArticleServiceServer) List(ctx context.Context, req *PageParam) (*Articles, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (*UnimplementedArticleServiceServer) Update(ctx context.Context, req *Article) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (*UnimplementedArticleServiceServer) Delete(ctx context.Context, req *ArticleId) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (*UnimplementedArticleServiceServer) Detail(ctx context.Context, req *ArticleId) (*Article, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Detail not implemented")
+}
+func (*UnimplementedArticleServiceServer) ListRevisions(ctx context.Context, req *RevisionParam) (*Revisions, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRevisions not implemented")
+}
+func (*UnimplementedArticleServiceServer) GetRevision(ctx context.Context, req *RevisionId) (*Revision, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRevision not implemented")
+}
+func (*UnimplementedArticleServiceServer) DiffRevisions(ctx context.Context, req *DiffParam) (*RevisionDiff, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffRevisions not implemented")
+}
+func (*UnimplementedArticleServiceServer) Rollback(ctx context.Context, req *RollbackParam) (*Revision, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rollback not implemented")
+}
+
+func RegisterArticleServiceServer(s *grpc.Server, srv ArticleServiceServer) {
+	s.RegisterService(&_ArticleService_serviceDesc, srv)
+}
+
+func _ArticleService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Article)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleServiceServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pbs.ArticleService/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleServiceServer).Create(ctx, req.(*Article))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleService_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PageParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleServiceServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pbs.ArticleService/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleServiceServer).List(ctx, req.(*PageParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleService_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Article)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleServiceServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pbs.ArticleService/Update",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleServiceServer).Update(ctx, req.(*Article))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArticleId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pbs.ArticleService/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleServiceServer).Delete(ctx, req.(*ArticleId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleService_Detail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArticleId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleServiceServer).Detail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pbs.ArticleService/Detail",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleServiceServer).Detail(ctx, req.(*ArticleId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleService_ListRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevisionParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleServiceServer).ListRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pbs.ArticleService/ListRevisions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleServiceServer).ListRevisions(ctx, req.(*RevisionParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleService_GetRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevisionId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleServiceServer).GetRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pbs.ArticleService/GetRevision",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleServiceServer).GetRevision(ctx, req.(*RevisionId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleService_DiffRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleServiceServer).DiffRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pbs.ArticleService/DiffRevisions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleServiceServer).DiffRevisions(ctx, req.(*DiffParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleService_Rollback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleServiceServer).Rollback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pbs.ArticleService/Rollback",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleServiceServer).Rollback(ctx, req.(*RollbackParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ArticleService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pbs.ArticleService",
+	HandlerType: (*ArticleServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _ArticleService_Create_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _ArticleService_List_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _ArticleService_Update_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _ArticleService_Delete_Handler,
+		},
+		{
+			MethodName: "Detail",
+			Handler:    _ArticleService_Detail_Handler,
+		},
+		{
+			MethodName: "ListRevisions",
+			Handler:    _ArticleService_ListRevisions_Handler,
+		},
+		{
+			MethodName: "GetRevision",
+			Handler:    _ArticleService_GetRevision_Handler,
+		},
+		{
+			MethodName: "DiffRevisions",
+			Handler:    _ArticleService_DiffRevisions_Handler,
+		},
+		{
+			MethodName: "Rollback",
+			Handler:    _ArticleService_Rollback_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "article.proto",
+}
+
+func (m *ArticleId) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ArticleId) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ArticleId) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintArticle(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Articles) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Articles) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Articles) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Count != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.Count))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Data) > 0 {
+		for iNdEx := len(m.Data) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Data[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintArticle(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Empty) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Empty) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Empty) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *PageParam) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PageParam) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PageParam) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Types) > 0 {
+		dAtA2 := make([]byte, len(m.Types)*10)
+		var j1 int
+		for _, num := range m.Types {
+			for num >= 1<<7 {
+				dAtA2[j1] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j1++
+			}
+			dAtA2[j1] = uint8(num)
+			j1++
+		}
+		i -= j1
+		copy(dAtA[i:], dAtA2[:j1])
+		i = encodeVarintArticle(dAtA, i, uint64(j1))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.PageSize != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.PageSize))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Page != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.Page))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Article) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Article) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Article) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Version != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x58
+	}
+	if m.UpdateMask != nil {
+		{
+			size, err := m.UpdateMask.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintArticle(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x52
+	}
+	if len(m.CoverImg) > 0 {
+		i -= len(m.CoverImg)
+		copy(dAtA[i:], m.CoverImg)
+		i = encodeVarintArticle(dAtA, i, uint64(len(m.CoverImg)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if m.DeletedAt != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.DeletedAt))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.UpdatedAt != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.UpdatedAt))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.CreatedAt != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.CreatedAt))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.ArticleType != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.ArticleType))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Content) > 0 {
+		i -= len(m.Content)
+		copy(dAtA[i:], m.Content)
+		i = encodeVarintArticle(dAtA, i, uint64(len(m.Content)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Title) > 0 {
+		i -= len(m.Title)
+		copy(dAtA[i:], m.Title)
+		i = encodeVarintArticle(dAtA, i, uint64(len(m.Title)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintArticle(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Revision) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Revision) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Revision) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.CoverImg) > 0 {
+		i -= len(m.CoverImg)
+		copy(dAtA[i:], m.CoverImg)
+		i = encodeVarintArticle(dAtA, i, uint64(len(m.CoverImg)))
+		i--
+		dAtA[i] = 0x62
+	}
+	if m.ArticleType != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.ArticleType))
+		i--
+		dAtA[i] = 0x58
+	}
+	if len(m.Content) > 0 {
+		i -= len(m.Content)
+		copy(dAtA[i:], m.Content)
+		i = encodeVarintArticle(dAtA, i, uint64(len(m.Content)))
+		i--
+		dAtA[i] = 0x52
+	}
+	if len(m.Title) > 0 {
+		i -= len(m.Title)
+		copy(dAtA[i:], m.Title)
+		i = encodeVarintArticle(dAtA, i, uint64(len(m.Title)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if len(m.Changed) > 0 {
+		for iNdEx := len(m.Changed) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Changed[iNdEx])
+			copy(dAtA[i:], m.Changed[iNdEx])
+			i = encodeVarintArticle(dAtA, i, uint64(len(m.Changed[iNdEx])))
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if m.RollbackVersion != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.RollbackVersion))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.CreatedAt != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.CreatedAt))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.Author) > 0 {
+		i -= len(m.Author)
+		copy(dAtA[i:], m.Author)
+		i = encodeVarintArticle(dAtA, i, uint64(len(m.Author)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.Action) > 0 {
+		i -= len(m.Action)
+		copy(dAtA[i:], m.Action)
+		i = encodeVarintArticle(dAtA, i, uint64(len(m.Action)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Version != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.ArticleId) > 0 {
+		i -= len(m.ArticleId)
+		copy(dAtA[i:], m.ArticleId)
+		i = encodeVarintArticle(dAtA, i, uint64(len(m.ArticleId)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintArticle(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Revisions) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Revisions) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Revisions) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Count != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.Count))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Data) > 0 {
+		for iNdEx := len(m.Data) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Data[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintArticle(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RevisionParam) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RevisionParam) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RevisionParam) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.PageSize != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.PageSize))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Page != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.Page))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.ArticleId) > 0 {
+		i -= len(m.ArticleId)
+		copy(dAtA[i:], m.ArticleId)
+		i = encodeVarintArticle(dAtA, i, uint64(len(m.ArticleId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RevisionId) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RevisionId) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RevisionId) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Version != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.ArticleId) > 0 {
+		i -= len(m.ArticleId)
+		copy(dAtA[i:], m.ArticleId)
+		i = encodeVarintArticle(dAtA, i, uint64(len(m.ArticleId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DiffParam) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DiffParam) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DiffParam) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.To != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.To))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.From != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.From))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.ArticleId) > 0 {
+		i -= len(m.ArticleId)
+		copy(dAtA[i:], m.ArticleId)
+		i = encodeVarintArticle(dAtA, i, uint64(len(m.ArticleId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RevisionDiff) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RevisionDiff) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RevisionDiff) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Fields) > 0 {
+		for iNdEx := len(m.Fields) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Fields[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintArticle(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.To != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.To))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.From != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.From))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *FieldDiff) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FieldDiff) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FieldDiff) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Deleted != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.Deleted))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Added != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.Added))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Diff) > 0 {
+		i -= len(m.Diff)
+		copy(dAtA[i:], m.Diff)
+		i = encodeVarintArticle(dAtA, i, uint64(len(m.Diff)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Field) > 0 {
+		i -= len(m.Field)
+		copy(dAtA[i:], m.Field)
+		i = encodeVarintArticle(dAtA, i, uint64(len(m.Field)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RollbackParam) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RollbackParam) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RollbackParam) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.CurrentVersion != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.CurrentVersion))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Version != 0 {
+		i = encodeVarintArticle(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.ArticleId) > 0 {
+		i -= len(m.ArticleId)
+		copy(dAtA[i:], m.ArticleId)
+		i = encodeVarintArticle(dAtA, i, uint64(len(m.ArticleId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintArticle(dAtA []byte, offset int, v uint64) int {
+	offset -= sovArticle(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *ArticleId) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovArticle(uint64(l))
+	}
+	return n
+}
+
+func (m *Articles) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Data) > 0 {
+		for _, e := range m.Data {
+			l = e.Size()
+			n += 1 + l + sovArticle(uint64(l))
+		}
+	}
+	if m.Count != 0 {
+		n += 1 + sovArticle(uint64(m.Count))
+	}
+	return n
+}
+
+func (m *Empty) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *PageParam) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Page != 0 {
+		n += 1 + sovArticle(uint64(m.Page))
+	}
+	if m.PageSize != 0 {
+		n += 1 + sovArticle(uint64(m.PageSize))
+	}
+	if len(m.Types) > 0 {
+		l = 0
+		for _, e := range m.Types {
+			l += sovArticle(uint64(e))
+		}
+		n += 1 + sovArticle(uint64(l)) + l
+	}
+	return n
+}
+
+func (m *Article) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovArticle(uint64(l))
+	}
+	l = len(m.Title)
+	if l > 0 {
+		n += 1 + l + sovArticle(uint64(l))
+	}
+	l = len(m.Content)
+	if l > 0 {
+		n += 1 + l + sovArticle(uint64(l))
+	}
+	if m.ArticleType != 0 {
+		n += 1 + sovArticle(uint64(m.ArticleType))
+	}
+	if m.CreatedAt != 0 {
+		n += 1 + sovArticle(uint64(m.CreatedAt))
+	}
+	if m.UpdatedAt != 0 {
+		n += 1 + sovArticle(uint64(m.UpdatedAt))
+	}
+	if m.DeletedAt != 0 {
+		n += 1 + sovArticle(uint64(m.DeletedAt))
+	}
+	l = len(m.CoverImg)
+	if l > 0 {
+		n += 1 + l + sovArticle(uint64(l))
+	}
+	if m.UpdateMask != nil {
+		l = m.UpdateMask.Size()
+		n += 1 + l + sovArticle(uint64(l))
+	}
+	if m.Version != 0 {
+		n += 1 + sovArticle(uint64(m.Version))
+	}
+	return n
+}
+
+func (m *Revision) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovArticle(uint64(l))
+	}
+	l = len(m.ArticleId)
+	if l > 0 {
+		n += 1 + l + sovArticle(uint64(l))
+	}
+	if m.Version != 0 {
+		n += 1 + sovArticle(uint64(m.Version))
+	}
+	l = len(m.Action)
+	if l > 0 {
+		n += 1 + l + sovArticle(uint64(l))
+	}
+	l = len(m.Author)
+	if l > 0 {
+		n += 1 + l + sovArticle(uint64(l))
+	}
+	if m.CreatedAt != 0 {
+		n += 1 + sovArticle(uint64(m.CreatedAt))
+	}
+	if m.RollbackVersion != 0 {
+		n += 1 + sovArticle(uint64(m.RollbackVersion))
+	}
+	if len(m.Changed) > 0 {
+		for _, s := range m.Changed {
+			l = len(s)
+			n += 1 + l + sovArticle(uint64(l))
+		}
+	}
+	l = len(m.Title)
+	if l > 0 {
+		n += 1 + l + sovArticle(uint64(l))
+	}
+	l = len(m.Content)
+	if l > 0 {
+		n += 1 + l + sovArticle(uint64(l))
+	}
+	if m.ArticleType != 0 {
+		n += 1 + sovArticle(uint64(m.ArticleType))
+	}
+	l = len(m.CoverImg)
+	if l > 0 {
+		n += 1 + l + sovArticle(uint64(l))
+	}
+	return n
+}
+
+func (m *Revisions) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Data) > 0 {
+		for _, e := range m.Data {
+			l = e.Size()
+			n += 1 + l + sovArticle(uint64(l))
+		}
+	}
+	if m.Count != 0 {
+		n += 1 + sovArticle(uint64(m.Count))
+	}
+	return n
+}
+
+func (m *RevisionParam) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ArticleId)
+	if l > 0 {
+		n += 1 + l + sovArticle(uint64(l))
+	}
+	if m.Page != 0 {
+		n += 1 + sovArticle(uint64(m.Page))
+	}
+	if m.PageSize != 0 {
+		n += 1 + sovArticle(uint64(m.PageSize))
+	}
+	return n
+}
+
+func (m *RevisionId) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ArticleId)
+	if l > 0 {
+		n += 1 + l + sovArticle(uint64(l))
+	}
+	if m.Version != 0 {
+		n += 1 + sovArticle(uint64(m.Version))
+	}
+	return n
+}
+
+func (m *DiffParam) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ArticleId)
+	if l > 0 {
+		n += 1 + l + sovArticle(uint64(l))
+	}
+	if m.From != 0 {
+		n += 1 + sovArticle(uint64(m.From))
+	}
+	if m.To != 0 {
+		n += 1 + sovArticle(uint64(m.To))
+	}
+	return n
+}
+
+func (m *RevisionDiff) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.From != 0 {
+		n += 1 + sovArticle(uint64(m.From))
+	}
+	if m.To != 0 {
+		n += 1 + sovArticle(uint64(m.To))
+	}
+	if len(m.Fields) > 0 {
+		for _, e := range m.Fields {
+			l = e.Size()
+			n += 1 + l + sovArticle(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *FieldDiff) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Field)
+	if l > 0 {
+		n += 1 + l + sovArticle(uint64(l))
+	}
+	l = len(m.Diff)
+	if l > 0 {
+		n += 1 + l + sovArticle(uint64(l))
+	}
+	if m.Added != 0 {
+		n += 1 + sovArticle(uint64(m.Added))
+	}
+	if m.Deleted != 0 {
+		n += 1 + sovArticle(uint64(m.Deleted))
+	}
+	return n
+}
+
+func (m *RollbackParam) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ArticleId)
+	if l > 0 {
+		n += 1 + l + sovArticle(uint64(l))
+	}
+	if m.Version != 0 {
+		n += 1 + sovArticle(uint64(m.Version))
+	}
+	if m.CurrentVersion != 0 {
+		n += 1 + sovArticle(uint64(m.CurrentVersion))
+	}
+	return n
+}
+
+func sovArticle(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozArticle(x uint64) (n int) {
+	return sovArticle(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *ArticleId) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowArticle
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ArticleId: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ArticleId: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthArticle
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipArticle(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Articles) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowArticle
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Articles: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Articles: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthArticle
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = append(m.Data, &Article{})
+			if err := m.Data[len(m.Data)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipArticle(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Empty) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowArticle
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Empty: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Empty: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipArticle(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PageParam) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowArticle
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PageParam: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PageParam: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Page", wireType)
+			}
+			m.Page = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Page |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PageSize", wireType)
+			}
+			m.PageSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PageSize |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType == 0 {
+				var v ArticleType
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowArticle
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= ArticleType(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.Types = append(m.Types, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowArticle
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthArticle
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthArticle
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				if elementCount != 0 && len(m.Types) == 0 {
+					m.Types = make([]ArticleType, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v ArticleType
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowArticle
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= ArticleType(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.Types = append(m.Types, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Types", wireType)
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipArticle(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Article) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowArticle
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Article: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Article: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthArticle
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Title", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthArticle
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Title = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Content", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthArticle
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Content = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ArticleType", wireType)
+			}
+			m.ArticleType = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ArticleType |= ArticleType(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAt", wireType)
+			}
+			m.CreatedAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CreatedAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UpdatedAt", wireType)
+			}
+			m.UpdatedAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.UpdatedAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DeletedAt", wireType)
+			}
+			m.DeletedAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DeletedAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CoverImg", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthArticle
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CoverImg = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UpdateMask", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthArticle
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.UpdateMask == nil {
+				m.UpdateMask = &types.FieldMask{}
+			}
+			if err := m.UpdateMask.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipArticle(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Revision) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowArticle
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Revision: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Revision: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthArticle
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ArticleId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthArticle
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ArticleId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Action", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthArticle
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Action = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Author", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthArticle
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Author = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAt", wireType)
+			}
+			m.CreatedAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CreatedAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RollbackVersion", wireType)
+			}
+			m.RollbackVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RollbackVersion |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Changed", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthArticle
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Changed = append(m.Changed, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Title", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthArticle
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Title = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Content", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthArticle
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Content = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ArticleType", wireType)
+			}
+			m.ArticleType = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ArticleType |= ArticleType(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CoverImg", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthArticle
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CoverImg = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipArticle(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
 		}
 	}
-	if m.Count != 0 {
-		n += 1 + sovArticle(uint64(m.Count))
-	}
-	return n
-}
 
-func (m *Empty) Size() (n int) {
-	if m == nil {
-		return 0
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	var l int
-	_ = l
-	return n
+	return nil
 }
-
-func (m *PageParam) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Page != 0 {
-		n += 1 + sovArticle(uint64(m.Page))
-	}
-	if m.PageSize != 0 {
-		n += 1 + sovArticle(uint64(m.PageSize))
+func (m *Revisions) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowArticle
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Revisions: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Revisions: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthArticle
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = append(m.Data, &Revision{})
+			if err := m.Data[len(m.Data)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipArticle(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
 	}
-	return n
-}
 
-func (m *Article) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Id)
-	if l > 0 {
-		n += 1 + l + sovArticle(uint64(l))
-	}
-	l = len(m.Title)
-	if l > 0 {
-		n += 1 + l + sovArticle(uint64(l))
-	}
-	l = len(m.Content)
-	if l > 0 {
-		n += 1 + l + sovArticle(uint64(l))
-	}
-	if m.ArticleType != 0 {
-		n += 1 + sovArticle(uint64(m.ArticleType))
-	}
-	if m.CreatedAt != 0 {
-		n += 1 + sovArticle(uint64(m.CreatedAt))
-	}
-	if m.UpdatedAt != 0 {
-		n += 1 + sovArticle(uint64(m.UpdatedAt))
-	}
-	if m.DeletedAt != 0 {
-		n += 1 + sovArticle(uint64(m.DeletedAt))
-	}
-	l = len(m.CoverImg)
-	if l > 0 {
-		n += 1 + l + sovArticle(uint64(l))
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
-}
-
-func sovArticle(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozArticle(x uint64) (n int) {
-	return sovArticle(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	return nil
 }
-func (m *ArticleId) Unmarshal(dAtA []byte) error {
+func (m *RevisionParam) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1068,15 +3680,15 @@ func (m *ArticleId) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ArticleId: wiretype end group for non-group")
+			return fmt.Errorf("proto: RevisionParam: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ArticleId: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: RevisionParam: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ArticleId", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1104,18 +3716,53 @@ func (m *ArticleId) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Id = string(dAtA[iNdEx:postIndex])
+			m.ArticleId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Page", wireType)
+			}
+			m.Page = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Page |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PageSize", wireType)
+			}
+			m.PageSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PageSize |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipArticle(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthArticle
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthArticle
 			}
 			if (iNdEx + skippy) > l {
@@ -1130,7 +3777,7 @@ func (m *ArticleId) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Articles) Unmarshal(dAtA []byte) error {
+func (m *RevisionId) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1153,17 +3800,17 @@ func (m *Articles) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Articles: wiretype end group for non-group")
+			return fmt.Errorf("proto: RevisionId: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Articles: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: RevisionId: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ArticleId", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowArticle
@@ -1173,31 +3820,29 @@ func (m *Articles) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthArticle
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthArticle
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Data = append(m.Data, &Article{})
-			if err := m.Data[len(m.Data)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.ArticleId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
 			}
-			m.Count = 0
+			m.Version = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowArticle
@@ -1207,7 +3852,7 @@ func (m *Articles) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Count |= int64(b&0x7F) << shift
+				m.Version |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -1218,10 +3863,7 @@ func (m *Articles) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthArticle
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthArticle
 			}
 			if (iNdEx + skippy) > l {
@@ -1236,7 +3878,7 @@ func (m *Articles) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Empty) Unmarshal(dAtA []byte) error {
+func (m *DiffParam) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1259,22 +3901,89 @@ func (m *Empty) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Empty: wiretype end group for non-group")
+			return fmt.Errorf("proto: DiffParam: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Empty: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DiffParam: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ArticleId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthArticle
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ArticleId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
+			}
+			m.From = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.From |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field To", wireType)
+			}
+			m.To = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.To |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipArticle(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthArticle
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthArticle
 			}
 			if (iNdEx + skippy) > l {
@@ -1289,7 +3998,7 @@ func (m *Empty) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PageParam) Unmarshal(dAtA []byte) error {
+func (m *RevisionDiff) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1312,17 +4021,17 @@ func (m *PageParam) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PageParam: wiretype end group for non-group")
+			return fmt.Errorf("proto: RevisionDiff: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PageParam: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: RevisionDiff: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Page", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
 			}
-			m.Page = 0
+			m.From = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowArticle
@@ -1332,16 +4041,16 @@ func (m *PageParam) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Page |= int64(b&0x7F) << shift
+				m.From |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field PageSize", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field To", wireType)
 			}
-			m.PageSize = 0
+			m.To = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowArticle
@@ -1351,21 +4060,52 @@ func (m *PageParam) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.PageSize |= int64(b&0x7F) << shift
+				m.To |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fields", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowArticle
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthArticle
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Fields = append(m.Fields, &FieldDiff{})
+			if err := m.Fields[len(m.Fields)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipArticle(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthArticle
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthArticle
 			}
 			if (iNdEx + skippy) > l {
@@ -1380,7 +4120,7 @@ func (m *PageParam) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Article) Unmarshal(dAtA []byte) error {
+func (m *FieldDiff) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1403,15 +4143,15 @@ func (m *Article) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Article: wiretype end group for non-group")
+			return fmt.Errorf("proto: FieldDiff: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Article: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: FieldDiff: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1439,11 +4179,11 @@ func (m *Article) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Id = string(dAtA[iNdEx:postIndex])
+			m.Field = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Title", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Diff", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1471,13 +4211,13 @@ func (m *Article) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Title = string(dAtA[iNdEx:postIndex])
+			m.Diff = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Content", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Added", wireType)
 			}
-			var stringLen uint64
+			m.Added = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowArticle
@@ -1487,29 +4227,16 @@ func (m *Article) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Added |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthArticle
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthArticle
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Content = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ArticleType", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Deleted", wireType)
 			}
-			m.ArticleType = 0
+			m.Deleted = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowArticle
@@ -1519,35 +4246,66 @@ func (m *Article) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.ArticleType |= uint32(b&0x7F) << shift
+				m.Deleted |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 6:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAt", wireType)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipArticle(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			m.CreatedAt = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowArticle
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.CreatedAt |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthArticle
 			}
-		case 7:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field UpdatedAt", wireType)
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
 			}
-			m.UpdatedAt = 0
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RollbackParam) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowArticle
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RollbackParam: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RollbackParam: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ArticleId", wireType)
+			}
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowArticle
@@ -1557,16 +4315,29 @@ func (m *Article) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.UpdatedAt |= int64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 8:
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthArticle
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthArticle
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ArticleId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DeletedAt", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
 			}
-			m.DeletedAt = 0
+			m.Version = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowArticle
@@ -1576,16 +4347,16 @@ func (m *Article) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.DeletedAt |= int64(b&0x7F) << shift
+				m.Version |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 9:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CoverImg", wireType)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CurrentVersion", wireType)
 			}
-			var stringLen uint64
+			m.CurrentVersion = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowArticle
@@ -1595,34 +4366,18 @@ func (m *Article) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.CurrentVersion |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthArticle
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthArticle
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.CoverImg = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipArticle(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthArticle
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthArticle
 			}
 			if (iNdEx + skippy) > l {
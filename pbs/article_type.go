@@ -0,0 +1,53 @@
+package pbs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// IsValid 是否为已定义的文章类型
+func (x ArticleType) IsValid() bool {
+	_, ok := ArticleType_name[int32(x)]
+	return ok
+}
+
+// articleTypePrefix 枚举名称的公共前缀，解析时可以省略
+const articleTypePrefix = "ARTICLE_TYPE_"
+
+// ParseArticleType 解析文章类型，支持名称（不区分大小写，可省略 ARTICLE_TYPE_ 前缀）和数字
+func ParseArticleType(s string) (ArticleType, error) {
+	s = strings.TrimSpace(s)
+	name := strings.ToUpper(s)
+	if v, ok := ArticleType_value[name]; ok {
+		return ArticleType(v), nil
+	}
+	if v, ok := ArticleType_value[articleTypePrefix+name]; ok {
+		return ArticleType(v), nil
+	}
+	n, err := strconv.ParseInt(s, 10, 32)
+	if err != nil || !ArticleType(n).IsValid() {
+		return 0, fmt.Errorf("invalid article type %q", s)
+	}
+	return ArticleType(n), nil
+}
+
+// UnmarshalJSON 兼容数字和名称两种写法，输出仍为数字，与已有的客户端保持一致
+func (x *ArticleType) UnmarshalJSON(b []byte) error {
+	var n int32
+	if err := json.Unmarshal(b, &n); err == nil {
+		*x = ArticleType(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("article type must be a number or name: %s", b)
+	}
+	v, err := ParseArticleType(s)
+	if err != nil {
+		return err
+	}
+	*x = v
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"playGround/storage"
+	"playGround/upload"
+	"playGround/utils"
+	"strings"
+)
+
+// previewPrefix 压缩包预览的路由前缀
+const previewPrefix = "/preview/"
+
+// 预览页面的内容安全策略，上传的页面不可信，放在无同源权限的沙箱中运行，只允许加载包内资源
+const (
+	previewHTMLPolicy = "default-src 'self'; script-src 'self' 'unsafe-inline' 'unsafe-eval' 'wasm-unsafe-eval' blob:; " +
+		"style-src 'self' 'unsafe-inline'; img-src 'self' data: blob:; media-src 'self' data: blob:; font-src 'self' data:; " +
+		"connect-src 'self' data: blob:; worker-src 'self' blob:; object-src 'none'; base-uri 'none'; form-action 'none'; " +
+		"frame-ancestors 'self'; sandbox allow-scripts"
+	previewAssetPolicy = "default-src 'none'; style-src 'unsafe-inline'; sandbox"
+)
+
+// previewPath 压缩包入口页面的预览地址，index为空时返回空字符串
+func previewPath(blobId, index string) string {
+	if index == "" {
+		return ""
+	}
+	return previewPrefix + blobId + "/" + index
+}
+
+// Preview 返回压缩包解压后的文件，文件按内容哈希保存，内容不会变化，可以长期缓存
+// 沙箱中页面的来源为null，包内资源按跨域请求加载，跨域响应头由 middleware.CORS 按配置返回，限制来源时需允许null
+func Preview(w http.ResponseWriter, r *http.Request) {
+	blobId := r.PathValue("blob")
+	if !upload.ValidBlobId(blobId) {
+		utils.HTTPError(w, r, utils.NewError(utils.ErrNotFound, "文件不存在", nil))
+		return
+	}
+	name := r.PathValue("path")
+	for _, seg := range strings.Split(name, "/") {
+		if seg == ".." || strings.Contains(seg, "\\") {
+			utils.HTTPError(w, r, utils.NewError(utils.ErrInvalidParam, "非法的文件路径", nil))
+			return
+		}
+	}
+	// 目录请求返回目录下的 index.html
+	if name == "" || strings.HasSuffix(name, "/") {
+		name += "index.html"
+	}
+	key := upload.BlobPrefix(blobId) + path.Clean(name)
+	info, err := store.Stat(r.Context(), key)
+	if err != nil {
+		utils.HTTPError(w, r, previewError(err))
+		return
+	}
+	f, err := store.Get(r.Context(), key)
+	if err != nil {
+		utils.HTTPError(w, r, previewError(err))
+		return
+	}
+	defer f.Close()
+
+	ctype := storage.ContentType(key)
+	h := w.Header()
+	h.Set("Content-Type", ctype)
+	h.Set("X-Content-Type-Options", "nosniff")
+	h.Set("Referrer-Policy", "no-referrer")
+	h.Set("Cache-Control", "public, max-age=31536000, immutable")
+	h.Set("ETag", `"`+utils.Md5(key)+`"`)
+	if strings.HasPrefix(ctype, "text/html") {
+		h.Set("Content-Security-Policy", previewHTMLPolicy)
+	} else {
+		h.Set("Content-Security-Policy", previewAssetPolicy)
+	}
+	if rs, ok := f.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, "", info.ModTime, rs)
+		return
+	}
+	if t := info.ModTime; !t.IsZero() {
+		h.Set("Last-Modified", t.UTC().Format(http.TimeFormat))
+	}
+	if _, err = io.Copy(w, f); err != nil {
+		utils.WithContext(r.Context()).WithError(err).Warn("write preview err")
+	}
+}
+
+// previewError 文件不存在时返回404，其他错误按存储错误处理
+func previewError(err error) error {
+	if errors.Is(err, storage.ErrNotExist) || errors.Is(err, utils.ErrInvalidParam) {
+		return utils.NewError(utils.ErrNotFound, "文件不存在", err)
+	}
+	return utils.NewError(utils.ErrStorage, "读取文件失败", err)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func downloadProgram(t *testing.T, body io.Reader) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	DownloadProgram(w, httptest.NewRequest(http.MethodPost, "/program/download", body))
+	return w
+}
+
+func programBody(id string, program map[string]string) io.Reader {
+	data, _ := json.Marshal(map[string]interface{}{"score_standard_id": id, "program": program})
+	return bytes.NewReader(data)
+}
+
+func TestDownloadProgram(t *testing.T) {
+	w := downloadProgram(t, programBody("s1", map[string]string{"main": "G00 X0"}))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := zr.Open("s1/main.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := io.ReadAll(f); string(data) != "G00 X0" {
+		t.Fatalf("main.txt = %q", data)
+	}
+}
+
+func TestDownloadProgramLimits(t *testing.T) {
+	many := make(map[string]string, maxProgramEntries+1)
+	for i := 0; i <= maxProgramEntries; i++ {
+		many[fmt.Sprintf("p%d", i)] = ""
+	}
+	big := map[string]string{"a": strings.Repeat("x", maxProgramSize/2), "b": strings.Repeat("x", maxProgramSize/2)}
+	tests := []struct {
+		name   string
+		body   io.Reader
+		status int
+	}{
+		{"body too large", io.MultiReader(strings.NewReader(`{"program":{"a":"`), strings.NewReader(strings.Repeat("x", maxProgramBodySize))), http.StatusRequestEntityTooLarge},
+		{"too many entries", programBody("s1", many), http.StatusRequestEntityTooLarge},
+		{"content too large", programBody("s1", big), http.StatusRequestEntityTooLarge},
+		{"bad name", programBody("s1", map[string]string{"../a": "x"}), http.StatusBadRequest},
+		{"bad json", strings.NewReader("{"), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := downloadProgram(t, tt.body)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if w.Header().Get("Content-Disposition") != "" {
+				t.Fatal("error response sent as attachment")
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"playGround/model"
+	"playGround/upload"
+	"playGround/utils"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// 上传记录分页的默认值
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// uploadInfo 上传记录及文件的访问地址
+type uploadInfo struct {
+	*model.Upload
+	URL      string `json:"url"`
+	ModelURL string `json:"model_url,omitempty"`
+}
+
+func newUploadInfo(u *model.Upload) *uploadInfo {
+	info := &uploadInfo{Upload: u, URL: store.URL(u.Key)}
+	if u.Index != "" {
+		info.ModelURL = previewPath(u.BlobId, u.Index)
+	}
+	return info
+}
+
+// ListUploads 分页查询上传记录，支持按类别、上传人、文件名关键字、文件类型和创建时间过滤
+func ListUploads(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var (
+		ints = map[string]int64{"page": 1, "page_size": defaultPageSize, "start": 0, "end": 0}
+		err  error
+	)
+	for key := range ints {
+		if v := q.Get(key); v != "" {
+			if ints[key], err = strconv.ParseInt(v, 10, 64); err != nil {
+				utils.HTTPError(w, r, utils.NewError(utils.ErrInvalidParam, "参数格式错误: "+key, err))
+				return
+			}
+		}
+	}
+	if ints["page_size"] <= 0 || ints["page_size"] > maxPageSize {
+		ints["page_size"] = defaultPageSize
+	}
+	where := model.UploadFilter{
+		Name:        q.Get("name"),
+		Uploader:    q.Get("uploader"),
+		Keyword:     q.Get("keyword"),
+		ContentType: q.Get("content_type"),
+		Start:       ints["start"],
+		End:         ints["end"],
+	}
+	rs, count, err := new(model.Upload).GetUploadList(where, ints["page"], ints["page_size"])
+	if err != nil {
+		utils.HTTPError(w, r, utils.NewError(utils.ErrStorage, "查询上传记录失败", err))
+		return
+	}
+	data := make([]*uploadInfo, 0, len(rs))
+	for _, u := range rs {
+		data = append(data, newUploadInfo(u))
+	}
+	err = JSON(w, &struct {
+		Data  []*uploadInfo `json:"data"`
+		Count int64         `json:"count"`
+	}{data, count})
+	if err != nil {
+		utils.WithContext(r.Context()).WithError(err).Error("response err")
+	}
+}
+
+// UploadDetail 查询单条上传记录
+func UploadDetail(w http.ResponseWriter, r *http.Request) {
+	u, err := new(model.Upload).View(r.PathValue("id"))
+	if err != nil {
+		utils.HTTPError(w, r, uploadRecordError(err))
+		return
+	}
+	if err = JSON(w, newUploadInfo(u)); err != nil {
+		utils.WithContext(r.Context()).WithError(err).Error("response err")
+	}
+}
+
+// DeleteUpload 删除上传记录，文件没有其他记录引用时一并删除
+func DeleteUpload(w http.ResponseWriter, r *http.Request) {
+	u, err := new(model.Upload).Delete(r.PathValue("id"))
+	if err != nil {
+		utils.HTTPError(w, r, uploadRecordError(err))
+		return
+	}
+	// 记录已删除，文件删除失败只记录日志，不影响结果
+	if err = upload.Release(r.Context(), store, u.BlobId); err != nil {
+		utils.WithContext(r.Context()).WithError(err).WithField("blob_id", u.BlobId).Warn("release blob err")
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// uploadRecordError 区分记录不存在和数据库错误
+func uploadRecordError(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return utils.NewError(utils.ErrNotFound, "上传记录不存在", err)
+	}
+	return utils.NewError(utils.ErrStorage, "查询上传记录失败", err)
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"playGround/middleware"
+	"playGround/utils"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Config HTTP服务配置，时间为0时使用默认值
+type Config struct {
+	ReadTimeout       time.Duration `yaml:"read_timeout"`        //读取整个请求的超时，大文件上传需要调大
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"` //读取请求头的超时
+	WriteTimeout      time.Duration `yaml:"write_timeout"`       //写响应的超时
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`        //keep-alive连接的空闲超时
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"`    //优雅退出时等待请求处理完成的最长时间
+	ShutdownDelay     time.Duration `yaml:"shutdown_delay"`      //健康检查置为退出中后继续接收请求的时间，等负载均衡摘除实例，负数时不等待
+}
+
+// 服务配置的默认值
+const (
+	defaultAddr              = ":8080"
+	defaultReadTimeout       = 10 * time.Minute
+	defaultReadHeaderTimeout = 10 * time.Second
+	defaultWriteTimeout      = 10 * time.Minute
+	defaultIdleTimeout       = 2 * time.Minute
+	defaultShutdownTimeout   = 30 * time.Second
+	defaultShutdownDelay     = 5 * time.Second
+)
+
+// closer 退出时关闭的资源
+type closer struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Server HTTP服务，收到SIGINT/SIGTERM后停止接收新请求，等待处理中的请求完成后再关闭依赖的资源
+type Server struct {
+	mux             *http.ServeMux
+	srv             *http.Server
+	shutdownTimeout time.Duration
+	shutdownDelay   time.Duration
+	drainHooks      []func()
+	closers         []closer
+}
+
+// New 创建HTTP服务，addr可以只填端口，mws依次套用在所有路由上，第一个在最外层
+func New(addr string, cfg Config, mws ...middleware.Middleware) *Server {
+	if addr == "" {
+		addr = defaultAddr
+	} else if !strings.Contains(addr, ":") {
+		addr = ":" + addr
+	}
+	s := &Server{
+		mux:             http.NewServeMux(),
+		shutdownTimeout: orDefault(cfg.ShutdownTimeout, defaultShutdownTimeout),
+		shutdownDelay:   cfg.ShutdownDelay,
+	}
+	if s.shutdownDelay == 0 {
+		s.shutdownDelay = defaultShutdownDelay
+	}
+	s.srv = &http.Server{
+		Addr:              addr,
+		Handler:           middleware.Chain(s.mux, mws...),
+		ReadTimeout:       orDefault(cfg.ReadTimeout, defaultReadTimeout),
+		ReadHeaderTimeout: orDefault(cfg.ReadHeaderTimeout, defaultReadHeaderTimeout),
+		WriteTimeout:      orDefault(cfg.WriteTimeout, defaultWriteTimeout),
+		IdleTimeout:       orDefault(cfg.IdleTimeout, defaultIdleTimeout),
+	}
+	return s
+}
+
+func orDefault(d, def time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+	return def
+}
+
+// Handle 注册路由，pattern同 http.ServeMux
+func (s *Server) Handle(pattern string, h http.Handler) {
+	s.mux.Handle(pattern, h)
+}
+
+// HandleFunc 注册路由，pattern同 http.ServeMux
+func (s *Server) HandleFunc(pattern string, h func(http.ResponseWriter, *http.Request)) {
+	s.mux.HandleFunc(pattern, h)
+}
+
+// Route 请求匹配的路由模式，没有匹配的路由时返回空字符串
+func (s *Server) Route(r *http.Request) string {
+	_, pattern := s.mux.Handler(r)
+	return pattern
+}
+
+// Handler 套用中间件后的处理器
+func (s *Server) Handler() http.Handler {
+	return s.srv.Handler
+}
+
+// BeforeShutdown 注册收到退出信号后立即执行的函数，如将健康检查置为退出中，之后等待 ShutdownDelay 再停止接收新请求
+func (s *Server) BeforeShutdown(fn func()) {
+	s.drainHooks = append(s.drainHooks, fn)
+}
+
+// OnShutdown 注册退出时关闭的资源，在所有请求处理完成后按注册的相反顺序关闭
+func (s *Server) OnShutdown(name string, fn func(ctx context.Context) error) {
+	s.closers = append(s.closers, closer{name: name, fn: fn})
+}
+
+// Run 启动服务并阻塞，直到ctx结束或收到退出信号，返回监听失败或关闭资源时的错误
+func (s *Server) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.srv.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve 在指定的监听上提供服务，退出流程同 Run
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		utils.Infof("http server listening on %s", ln.Addr())
+		errCh <- s.srv.Serve(ln)
+	}()
+	select {
+	case err := <-errCh:
+		// 监听异常退出，同样需要关闭资源
+		s.close()
+		return err
+	case <-ctx.Done():
+	}
+	stop()
+	for _, fn := range s.drainHooks {
+		fn()
+	}
+	if s.shutdownDelay > 0 {
+		utils.Infof("http server draining for %s", s.shutdownDelay)
+		time.Sleep(s.shutdownDelay)
+	}
+	utils.Infof("http server shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+	err := s.srv.Shutdown(shutdownCtx)
+	if err != nil {
+		utils.Warnf("http server shutdown err: %v", err)
+	}
+	if serr := <-errCh; !errors.Is(serr, http.ErrServerClosed) && err == nil {
+		err = serr
+	}
+	if cerr := s.close(); err == nil {
+		err = cerr
+	}
+	utils.Infof("http server stopped")
+	return err
+}
+
+// close 按注册的相反顺序关闭资源
+func (s *Server) close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+	var errs []error
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		c := s.closers[i]
+		if err := c.fn(ctx); err != nil {
+			utils.Errorf("close %s err: %v", c.name, err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestServeShutdownOrder(t *testing.T) {
+	s := New("127.0.0.1:0", Config{ShutdownDelay: 200 * time.Millisecond})
+	var draining atomic.Bool
+	s.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if draining.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	var order []string
+	s.BeforeShutdown(func() {
+		draining.Store(true)
+		order = append(order, "before")
+	})
+	s.OnShutdown("db", func(ctx context.Context) error {
+		order = append(order, "close")
+		return nil
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ctx, ln) }()
+	url := "http://" + ln.Addr().String() + "/readyz"
+	resp, err := http.Get(url)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("ready before shutdown: %v", err)
+	}
+	resp.Body.Close()
+
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	// 等待期间仍接收请求，健康检查已返回退出中
+	if resp, err = http.Get(url); err != nil {
+		t.Fatalf("request during shutdown delay: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("readyz = %d, want 503", resp.StatusCode)
+	}
+	if err = <-done; err != nil {
+		t.Fatal(err)
+	}
+	if len(order) != 2 || order[0] != "before" || order[1] != "close" {
+		t.Fatalf("order = %v", order)
+	}
+}
+
+func TestShutdownDelayDisabled(t *testing.T) {
+	if s := New("", Config{ShutdownDelay: -1}); s.shutdownDelay > 0 {
+		t.Fatalf("delay = %s, want none", s.shutdownDelay)
+	}
+	if s := New("", Config{}); s.shutdownDelay != defaultShutdownDelay {
+		t.Fatalf("delay = %s, want default", s.shutdownDelay)
+	}
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"playGround/model"
+	"playGround/pbs"
+	"playGround/utils"
+	"playGround/validate"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// 文章列表分页的默认值
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// 文章字段的长度限制
+const (
+	maxTitleLen    = 200
+	maxContentLen  = 100000
+	maxCoverImgLen = 1024
+)
+
+// updatableFields 部分更新时可以修改的字段
+var updatableFields = []string{"title", "content", "article_type", "cover_img"}
+
+// 参数校验的场景
+const (
+	sceneCreate = "article.create"
+	sceneUpdate = "article.update"
+	sceneList   = "article.list"
+	sceneId     = "article.id"
+
+	sceneRevisionList = "revision.list"
+	sceneRevisionId   = "revision.id"
+	sceneDiff         = "revision.diff"
+	sceneRollback     = "revision.rollback"
+)
+
+// diffContext 差异中每处修改前后保留的行数
+const diffContext = 3
+
+func init() {
+	validate.Register(sceneCreate, (*pbs.Article)(nil), articleRules(
+		validate.Field("id", validate.ReadOnly()),
+		validate.Field("version", validate.ReadOnly()),
+	)...)
+	validate.Register(sceneUpdate, (*pbs.Article)(nil), articleRules(
+		validate.Field("id", validate.Required(), validate.ObjectId()),
+		// 旧文档没有版本号，读取到的版本为0
+		validate.Field("version", validate.Range(0, math.MaxInt64)),
+	)...)
+	validate.Register(sceneList, (*pbs.PageParam)(nil),
+		validate.Field("page", validate.Range(0, math.MaxInt32)),
+		validate.Field("page_size", validate.Range(0, maxPageSize)),
+		validate.Field("types", validate.MaxLen(len(pbs.ArticleType_name)), validate.Enum(pbs.ArticleType_name)),
+	)
+	validate.Register(sceneId, (*pbs.ArticleId)(nil),
+		validate.Field("id", validate.Required(), validate.ObjectId()),
+	)
+	validate.Register(sceneRevisionList, (*pbs.RevisionParam)(nil),
+		validate.Field("article_id", validate.Required(), validate.ObjectId()),
+		validate.Field("page", validate.Range(0, math.MaxInt32)),
+		validate.Field("page_size", validate.Range(0, maxPageSize)),
+	)
+	// 旧文章首次修改前的内容保存为版本0
+	validate.Register(sceneRevisionId, (*pbs.RevisionId)(nil),
+		validate.Field("article_id", validate.Required(), validate.ObjectId()),
+		validate.Field("version", validate.Range(0, math.MaxInt64)),
+	)
+	validate.Register(sceneDiff, (*pbs.DiffParam)(nil),
+		validate.Field("article_id", validate.Required(), validate.ObjectId()),
+		validate.Field("from", validate.Range(0, math.MaxInt64)),
+		validate.Field("to", validate.Range(0, math.MaxInt64)),
+	)
+	validate.Register(sceneRollback, (*pbs.RollbackParam)(nil),
+		validate.Field("article_id", validate.Required(), validate.ObjectId()),
+		validate.Field("version", validate.Range(0, math.MaxInt64)),
+		validate.Field("current_version", validate.Range(0, math.MaxInt64)),
+	)
+}
+
+// articleRules 新建和修改共用的文章字段规则，时间字段由服务端维护
+func articleRules(id, version validate.FieldRules) []validate.FieldRules {
+	return []validate.FieldRules{
+		id,
+		version,
+		validate.Field("title", validate.Required(), validate.MaxLen(maxTitleLen)),
+		validate.Field("content", validate.MaxLen(maxContentLen)),
+		validate.Field("article_type", validate.Required(), validate.Enum(pbs.ArticleType_name)),
+		validate.Field("cover_img", validate.MaxLen(maxCoverImgLen)),
+		validate.Field("created_at", validate.ReadOnly()),
+		validate.Field("updated_at", validate.ReadOnly()),
+		validate.Field("deleted_at", validate.ReadOnly()),
+	}
+}
+
+// ArticleService 文章服务，gRPC和HTTP网关共用
+type ArticleService struct {
+	article *model.Article
+}
+
+var _ pbs.ArticleServiceServer = (*ArticleService)(nil)
+
+// NewArticleService 创建文章服务
+func NewArticleService() *ArticleService {
+	return &ArticleService{article: new(model.Article)}
+}
+
+func (s *ArticleService) Create(ctx context.Context, in *pbs.Article) (*pbs.Empty, error) {
+	if err := validate.Check(sceneCreate, in); err != nil {
+		return nil, err
+	}
+	if err := s.article.Create(ctx, in); err != nil {
+		return nil, dbError(ctx, err)
+	}
+	return &pbs.Empty{}, nil
+}
+
+func (s *ArticleService) List(ctx context.Context, in *pbs.PageParam) (*pbs.Articles, error) {
+	if err := validate.Check(sceneList, in); err != nil {
+		return nil, err
+	}
+	page, pageSize := in.Page, in.PageSize
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	rs, count, err := s.article.GetArticleList(ctx, page, pageSize, in.Types)
+	if err != nil {
+		return nil, dbError(ctx, err)
+	}
+	return &pbs.Articles{Data: rs, Count: count}, nil
+}
+
+// Update 修改文章，带update_mask时只修改列出的字段，可以设置为零值，否则修改所有非零值字段。
+// version需为读取到的版本号，成功后版本号加1，已被他人修改时返回 Aborted 和当前版本号
+func (s *ArticleService) Update(ctx context.Context, in *pbs.Article) (*pbs.Empty, error) {
+	if in.UpdateMask == nil {
+		if err := validate.Check(sceneUpdate, in); err != nil {
+			return nil, err
+		}
+		if err := s.article.Edit(ctx, in); err != nil {
+			return nil, dbError(ctx, err)
+		}
+		return &pbs.Empty{}, nil
+	}
+	paths := in.UpdateMask.Paths
+	if vs := validate.Mask("update_mask", paths, updatableFields...); len(vs) > 0 {
+		return nil, validate.Error(vs)
+	}
+	// 不在mask中的字段不会修改，也不校验
+	if err := validate.Check(sceneUpdate, in, append([]string{"id", "version"}, paths...)...); err != nil {
+		return nil, err
+	}
+	if err := s.article.EditFields(ctx, in, paths); err != nil {
+		return nil, dbError(ctx, err)
+	}
+	return &pbs.Empty{}, nil
+}
+
+func (s *ArticleService) Delete(ctx context.Context, in *pbs.ArticleId) (*pbs.Empty, error) {
+	if err := validate.Check(sceneId, in); err != nil {
+		return nil, err
+	}
+	if err := s.article.Delete(ctx, in.Id); err != nil {
+		return nil, dbError(ctx, err)
+	}
+	return &pbs.Empty{}, nil
+}
+
+func (s *ArticleService) Detail(ctx context.Context, in *pbs.ArticleId) (*pbs.Article, error) {
+	if err := validate.Check(sceneId, in); err != nil {
+		return nil, err
+	}
+	rs, err := s.article.View(ctx, in.Id)
+	if err != nil {
+		return nil, dbError(ctx, err)
+	}
+	return rs, nil
+}
+
+// ListRevisions 文章的修订历史，按版本号倒序，不含正文
+func (s *ArticleService) ListRevisions(ctx context.Context, in *pbs.RevisionParam) (*pbs.Revisions, error) {
+	if err := validate.Check(sceneRevisionList, in); err != nil {
+		return nil, err
+	}
+	page, pageSize := in.Page, in.PageSize
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	rs, count, err := s.article.Revisions(ctx, in.ArticleId, page, pageSize)
+	if err != nil {
+		return nil, dbError(ctx, err)
+	}
+	return &pbs.Revisions{Data: rs, Count: count}, nil
+}
+
+func (s *ArticleService) GetRevision(ctx context.Context, in *pbs.RevisionId) (*pbs.Revision, error) {
+	if err := validate.Check(sceneRevisionId, in); err != nil {
+		return nil, err
+	}
+	rs, err := s.article.Revision(ctx, in.ArticleId, in.Version)
+	if err != nil {
+		return nil, dbError(ctx, err)
+	}
+	return rs, nil
+}
+
+// DiffRevisions 比较两个版本，每个有变化的字段返回一段unified格式的按行差异
+func (s *ArticleService) DiffRevisions(ctx context.Context, in *pbs.DiffParam) (*pbs.RevisionDiff, error) {
+	if err := validate.Check(sceneDiff, in); err != nil {
+		return nil, err
+	}
+	from, err := s.article.Revision(ctx, in.ArticleId, in.From)
+	if err != nil {
+		return nil, dbError(ctx, err)
+	}
+	to, err := s.article.Revision(ctx, in.ArticleId, in.To)
+	if err != nil {
+		return nil, dbError(ctx, err)
+	}
+	rs := &pbs.RevisionDiff{From: in.From, To: in.To}
+	for _, f := range []struct{ name, from, to string }{
+		{"title", from.Title, to.Title},
+		{"content", from.Content, to.Content},
+		{"article_type", from.ArticleType.String(), to.ArticleType.String()},
+		{"cover_img", from.CoverImg, to.CoverImg},
+	} {
+		diff, added, deleted := utils.UnifiedDiff(
+			fmt.Sprintf("%s@%d", f.name, in.From), fmt.Sprintf("%s@%d", f.name, in.To), f.from, f.to, diffContext)
+		if diff != "" {
+			rs.Fields = append(rs.Fields, &pbs.FieldDiff{Field: f.name, Diff: diff, Added: int32(added), Deleted: int32(deleted)})
+		}
+	}
+	return rs, nil
+}
+
+// Rollback 将文章恢复为历史版本的内容，保存为新版本并返回新的修订。
+// current_version需为读取到的版本号，已被他人修改时返回 Aborted 和当前版本号
+func (s *ArticleService) Rollback(ctx context.Context, in *pbs.RollbackParam) (*pbs.Revision, error) {
+	if err := validate.Check(sceneRollback, in); err != nil {
+		return nil, err
+	}
+	rs, err := s.article.Rollback(ctx, in.ArticleId, in.Version, in.CurrentVersion)
+	if err != nil {
+		return nil, dbError(ctx, err)
+	}
+	return rs, nil
+}
+
+// versionConflictReason 版本冲突的错误原因
+const versionConflictReason = "VERSION_CONFLICT"
+
+// dbError 数据库错误转换为gRPC错误，文档不存在时返回NotFound，版本冲突时返回带当前版本号的Aborted
+func dbError(ctx context.Context, err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return utils.GRPCError(utils.NewError(utils.ErrNotFound, "文章不存在", err))
+	}
+	if errors.Is(err, model.ErrRevisionNotFound) {
+		return utils.GRPCError(utils.NewError(utils.ErrNotFound, "文章版本不存在", err))
+	}
+	var conflict *model.VersionConflictError
+	if errors.As(err, &conflict) {
+		st := status.New(utils.GRPCCode(utils.ErrConflict), "文章已被修改，请刷新后重试")
+		if ds, derr := st.WithDetails(&errdetails.ErrorInfo{
+			Reason:   versionConflictReason,
+			Domain:   "article",
+			Metadata: map[string]string{"current_version": strconv.FormatInt(conflict.Current, 10)},
+		}); derr == nil {
+			st = ds
+		}
+		return st.Err()
+	}
+	utils.WithContext(ctx).WithError(err).Error("article db err")
+	return utils.GRPCError(utils.NewError(utils.ErrInternal, "数据库错误", err))
+}
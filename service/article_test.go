@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"playGround/model"
+	"playGround/pbs"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const articleId = "507f1f77bcf86cd799439011"
+
+func TestUpdateMaskRejected(t *testing.T) {
+	s := NewArticleService()
+	tests := []struct {
+		name  string
+		in    *pbs.Article
+		field string
+	}{
+		{"empty mask", &pbs.Article{Id: articleId, UpdateMask: &types.FieldMask{}}, "update_mask"},
+		{"read only field", &pbs.Article{Id: articleId, UpdateMask: &types.FieldMask{Paths: []string{"title", "version"}}}, "update_mask"},
+		{"unknown field", &pbs.Article{Id: articleId, UpdateMask: &types.FieldMask{Paths: []string{"nope"}}}, "update_mask"},
+		// 只校验mask中的字段和ID
+		{"bad id", &pbs.Article{Id: "x", UpdateMask: &types.FieldMask{Paths: []string{"title"}}, Title: "t"}, "id"},
+		{"blank title in mask", &pbs.Article{Id: articleId, UpdateMask: &types.FieldMask{Paths: []string{"title"}}}, "title"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 校验不通过时不会访问数据库
+			_, err := s.Update(context.Background(), tt.in)
+			st := status.Convert(err)
+			if st.Code() != codes.InvalidArgument || len(st.Details()) != 1 {
+				t.Fatalf("err = %v", err)
+			}
+			br := st.Details()[0].(*errdetails.BadRequest)
+			if br.FieldViolations[0].Field != tt.field {
+				t.Fatalf("violations = %v", br.FieldViolations)
+			}
+		})
+	}
+}
+
+func TestCreateTypeRequired(t *testing.T) {
+	s := NewArticleService()
+	for _, typ := range []pbs.ArticleType{pbs.ArticleType_ARTICLE_TYPE_UNSPECIFIED, 9} {
+		_, err := s.Create(context.Background(), &pbs.Article{Title: "t", ArticleType: typ})
+		st := status.Convert(err)
+		if st.Code() != codes.InvalidArgument || len(st.Details()) != 1 {
+			t.Fatalf("type %d: err = %v", typ, err)
+		}
+		if br := st.Details()[0].(*errdetails.BadRequest); br.FieldViolations[0].Field != "article_type" {
+			t.Fatalf("type %d: violations = %v", typ, br.FieldViolations)
+		}
+	}
+}
+
+func TestDbErrorVersionConflict(t *testing.T) {
+	err := dbError(context.Background(), fmt.Errorf("edit: %w", &model.VersionConflictError{Current: 7}))
+	st := status.Convert(err)
+	if st.Code() != codes.Aborted || len(st.Details()) != 1 {
+		t.Fatalf("status = %v", st)
+	}
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	if !ok || info.Reason != versionConflictReason || info.Metadata["current_version"] != "7" {
+		t.Fatalf("details = %v", st.Details())
+	}
+}
+
+func TestDbError(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{mongo.ErrNoDocuments, codes.NotFound},
+		{model.ErrRevisionNotFound, codes.NotFound},
+		{errors.New("connection refused"), codes.Internal},
+	}
+	for _, tt := range tests {
+		if got := status.Code(dbError(context.Background(), tt.err)); got != tt.code {
+			t.Errorf("dbError(%v) = %v, want %v", tt.err, got, tt.code)
+		}
+	}
+}
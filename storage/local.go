@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"playGround/utils"
+	"strings"
+	"time"
+)
+
+// Local 本地文件系统存储
+type Local struct {
+	root    string
+	baseURL string
+}
+
+// NewLocal 创建本地存储，baseURL为root对外的访问地址
+func NewLocal(root, baseURL string) *Local {
+	if baseURL == "" {
+		baseURL = "/"
+	}
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	return &Local{root: root, baseURL: baseURL}
+}
+
+func (l *Local) path(key string) (string, string, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return "", "", err
+	}
+	return key, filepath.Join(l.root, filepath.FromSlash(key)), nil
+}
+
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	_, fpath, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err = utils.IsFolder(filepath.Dir(fpath)); err != nil {
+		return err
+	}
+	// 先写临时文件再重命名，避免读到写了一半的文件
+	tmp, err := os.CreateTemp(filepath.Dir(fpath), ".upload-*")
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(tmp, r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), fpath)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+func (l *Local) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	_, fpath, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(fpath)
+	if os.IsNotExist(err) {
+		return nil, ErrNotExist
+	}
+	return f, err
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	_, fpath, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(fpath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (l *Local) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	key, fpath, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(fpath)
+	if os.IsNotExist(err) || err == nil && info.IsDir() {
+		return nil, ErrNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ObjectInfo{Key: key, Size: info.Size(), ContentType: ContentType(key), ModTime: info.ModTime()}, nil
+}
+
+func (l *Local) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	prefix = strings.TrimPrefix(prefix, "/")
+	// 只遍历prefix所在的目录
+	dir := l.root
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		_, fpath, err := l.path(prefix[:i])
+		if err != nil {
+			return nil, err
+		}
+		dir = fpath
+	}
+	var rs []ObjectInfo
+	err := filepath.WalkDir(dir, func(fpath string, d fs.DirEntry, err error) error {
+		if os.IsNotExist(err) {
+			return filepath.SkipAll
+		}
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+		rel, err := filepath.Rel(l.root, fpath)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rs = append(rs, ObjectInfo{Key: key, Size: info.Size(), ContentType: ContentType(key), ModTime: info.ModTime()})
+		return nil
+	})
+	return rs, err
+}
+
+// SignedURL 本地文件由 baseURL 公开访问，没有访问控制，直接返回公开地址，expiry不起作用
+func (l *Local) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	key, _, err := l.path(key)
+	if err != nil {
+		return "", err
+	}
+	return l.URL(key), nil
+}
+
+func (l *Local) URL(key string) string {
+	return l.baseURL + path.Clean(strings.TrimPrefix(key, "/"))
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"playGround/utils"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocal(t *testing.T) {
+	l := NewLocal(t.TempDir(), "/files")
+	ctx := context.Background()
+	if err := l.Put(ctx, "a/b.txt", strings.NewReader("hello"), 5, "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+	rc, err := l.Get(ctx, "/a/b.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(data) != "hello" {
+		t.Fatalf("get = %q", data)
+	}
+	if list, err := l.List(ctx, "a/"); err != nil || len(list) != 1 || list[0].Key != "a/b.txt" {
+		t.Fatalf("list = %+v, %v", list, err)
+	}
+	if err = l.Delete(ctx, "a/b.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = l.Stat(ctx, "a/b.txt"); err != ErrNotExist {
+		t.Fatalf("stat deleted = %v", err)
+	}
+	// 目录不是文件
+	if _, err = l.Stat(ctx, "a"); err != ErrNotExist {
+		t.Fatalf("stat dir = %v", err)
+	}
+}
+
+func TestLocalInvalidKey(t *testing.T) {
+	l := NewLocal(t.TempDir(), "")
+	ctx := context.Background()
+	for _, key := range []string{"", "/", ".", "../a.txt", "a/../../b.txt", `..\a.txt`} {
+		if err := l.Put(ctx, key, strings.NewReader("x"), 1, ""); utils.ErrorKind(err) != utils.ErrInvalidParam {
+			t.Errorf("put %q: %v", key, err)
+		}
+		if _, err := l.SignedURL(ctx, key, time.Minute); utils.ErrorKind(err) != utils.ErrInvalidParam {
+			t.Errorf("signed url %q: %v", key, err)
+		}
+	}
+}
+
+func TestLocalSignedURL(t *testing.T) {
+	l := NewLocal(t.TempDir(), "https://cdn.example.com/files")
+	signed, err := l.SignedURL(context.Background(), "/a/b.txt", time.Minute)
+	if err != nil || signed != "https://cdn.example.com/files/a/b.txt" {
+		t.Fatalf("signed url = %s, %v", signed, err)
+	}
+}
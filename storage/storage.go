@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"playGround/utils"
+	"strings"
+	"time"
+)
+
+// ErrNotExist 文件不存在
+var ErrNotExist = utils.NewError(utils.ErrNotFound, "文件不存在", nil)
+
+// Config 存储配置
+type Config struct {
+	Type  string `yaml:"type"` //存储类型 local/s3，默认local
+	Local struct {
+		Root    string `yaml:"root"`     //本地存储根目录
+		BaseURL string `yaml:"base_url"` //根目录对外的访问地址
+	} `yaml:"local"`
+	S3 struct {
+		Endpoint  string `yaml:"endpoint"`   //服务地址，如 127.0.0.1:9000
+		AccessKey string `yaml:"access_key"` //访问密钥
+		SecretKey string `yaml:"secret_key"` //私有密钥
+		Bucket    string `yaml:"bucket"`     //存储桶
+		Region    string `yaml:"region"`     //区域
+		UseSSL    bool   `yaml:"use_ssl"`    //是否使用https
+		BaseURL   string `yaml:"base_url"`   //对外的访问地址，为空时使用服务地址
+	} `yaml:"s3"`
+}
+
+// ObjectInfo 文件信息
+type ObjectInfo struct {
+	Key         string    `json:"key"`
+	Size        int64     `json:"size"`
+	ContentType string    `json:"content_type"`
+	ModTime     time.Time `json:"mod_time"`
+}
+
+// Storage 文件存储，key为以/分隔的相对路径
+type Storage interface {
+	// Put 写入文件，size未知时传-1
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get 读取文件，调用方负责关闭
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete 删除文件，文件不存在时不报错
+	Delete(ctx context.Context, key string) error
+	// Stat 获取文件信息
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// List 列出key以prefix开头的所有文件
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// SignedURL 生成有时效的访问地址
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+	// URL 文件的公开访问地址
+	URL(key string) string
+}
+
+// New 按配置创建存储
+func New(ctx context.Context, cfg Config) (Storage, error) {
+	switch cfg.Type {
+	case "", "local":
+		return NewLocal(cfg.Local.Root, cfg.Local.BaseURL), nil
+	case "s3":
+		return NewS3(ctx, cfg)
+	}
+	return nil, fmt.Errorf("unknown storage type %q", cfg.Type)
+}
+
+// CleanKey 统一key的格式，拒绝跳出存储根目录的key
+func CleanKey(key string) (string, error) {
+	key = strings.ReplaceAll(key, "\\", "/")
+	for _, seg := range strings.Split(key, "/") {
+		if seg == ".." {
+			return "", utils.NewError(utils.ErrInvalidParam, "非法的文件路径", fmt.Errorf("key %q", key))
+		}
+	}
+	clean := strings.TrimPrefix(path.Clean("/"+key), "/")
+	if clean == "" {
+		return "", utils.NewError(utils.ErrInvalidParam, "非法的文件路径", fmt.Errorf("key %q", key))
+	}
+	return clean, nil
+}
+
+// ContentType 根据扩展名推断文件类型
+func ContentType(key string) string {
+	if t := mime.TypeByExtension(path.Ext(key)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+// PutFile 将本地文件写入存储
+func PutFile(ctx context.Context, s Storage, key, fpath string) error {
+	f, err := os.Open(fpath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	return s.Put(ctx, key, f, info.Size(), ContentType(key))
+}
+
+// PutDir 将本地目录下的所有文件写入存储，key为prefix加相对路径
+func PutDir(ctx context.Context, s Storage, dir, prefix string) error {
+	return filepath.WalkDir(dir, func(fpath string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, fpath)
+		if err != nil {
+			return err
+		}
+		return PutFile(ctx, s, path.Join(prefix, filepath.ToSlash(rel)), fpath)
+	})
+}
+
+// DeletePrefix 删除key以prefix开头的所有文件
+func DeletePrefix(ctx context.Context, s Storage, prefix string) error {
+	objects, err := s.List(ctx, prefix)
+	if err != nil {
+		return err
+	}
+	for _, o := range objects {
+		if err = s.Delete(ctx, o.Key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,340 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"playGround/utils"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Exporter 将结束的span发送到后端
+type Exporter interface {
+	Export(ctx context.Context, spans []SpanData) error
+	Shutdown(ctx context.Context) error
+}
+
+// 批量导出的默认值
+const (
+	defaultBatchSize    = 512
+	defaultBatchTimeout = 5 * time.Second
+	defaultQueueSize    = 2048
+	exportTimeout       = 10 * time.Second
+)
+
+// batcher 在后台批量导出span，队列满时丢弃，不阻塞业务
+type batcher struct {
+	exp     Exporter
+	size    int
+	timeout time.Duration
+	queue   chan SpanData
+	flushCh chan chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+	dropped atomic.Int64
+}
+
+func newBatcher(exp Exporter, opts Options) *batcher {
+	b := &batcher{
+		exp:     exp,
+		size:    opts.BatchSize,
+		timeout: opts.BatchTimeout,
+		flushCh: make(chan chan struct{}),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	if b.size <= 0 {
+		b.size = defaultBatchSize
+	}
+	if b.timeout <= 0 {
+		b.timeout = defaultBatchTimeout
+	}
+	queueSize := opts.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+	b.queue = make(chan SpanData, queueSize)
+	go b.run()
+	return b
+}
+
+func (b *batcher) enqueue(s SpanData) {
+	select {
+	case b.queue <- s:
+	default:
+		b.dropped.Add(1)
+	}
+}
+
+func (b *batcher) run() {
+	defer close(b.stopped)
+	ticker := time.NewTicker(b.timeout)
+	defer ticker.Stop()
+	buf := make([]SpanData, 0, b.size)
+	export := func() {
+		if len(buf) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+		if err := b.exp.Export(ctx, buf); err != nil {
+			utils.Warnf("trace export %d spans err: %v", len(buf), err)
+		}
+		cancel()
+		buf = make([]SpanData, 0, b.size)
+	}
+	// drain 取出队列中已有的span
+	drain := func() {
+		for {
+			select {
+			case s := <-b.queue:
+				if buf = append(buf, s); len(buf) >= b.size {
+					export()
+				}
+			default:
+				return
+			}
+		}
+	}
+	for {
+		select {
+		case s := <-b.queue:
+			if buf = append(buf, s); len(buf) >= b.size {
+				export()
+			}
+		case <-ticker.C:
+			export()
+		case ch := <-b.flushCh:
+			drain()
+			export()
+			close(ch)
+		case <-b.done:
+			drain()
+			export()
+			return
+		}
+	}
+}
+
+func (b *batcher) flush(ctx context.Context) error {
+	ch := make(chan struct{})
+	select {
+	case b.flushCh <- ch:
+	case <-b.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *batcher) shutdown(ctx context.Context) error {
+	b.once.Do(func() { close(b.done) })
+	select {
+	case <-b.stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if n := b.dropped.Load(); n > 0 {
+		utils.Warnf("trace dropped %d spans because the queue was full", n)
+	}
+	return b.exp.Shutdown(ctx)
+}
+
+// StdoutExporter 每个span输出一行JSON，用于调试
+type StdoutExporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewStdoutExporter 创建输出到w的导出器，w为nil时输出到标准输出
+func NewStdoutExporter(w io.Writer) *StdoutExporter {
+	if w == nil {
+		w = os.Stdout
+	}
+	return &StdoutExporter{w: w}
+}
+
+// stdoutSpan 输出时ID使用十六进制字符串
+type stdoutSpan struct {
+	TraceID      string `json:"trace_id"`
+	SpanID       string `json:"span_id"`
+	ParentSpanID string `json:"parent_span_id,omitempty"`
+	SpanData
+	Duration string `json:"duration"`
+}
+
+func (e *StdoutExporter) Export(ctx context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	enc := json.NewEncoder(e.w)
+	for _, s := range spans {
+		out := stdoutSpan{TraceID: s.TraceID.String(), SpanID: s.SpanID.String(), SpanData: s, Duration: s.End.Sub(s.Start).String()}
+		if s.ParentSpanID.IsValid() {
+			out.ParentSpanID = s.ParentSpanID.String()
+		}
+		if err := enc.Encode(out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *StdoutExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// InMemoryExporter 将span保存在内存中，用于测试
+type InMemoryExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+// NewInMemoryExporter 创建内存导出器
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+func (e *InMemoryExporter) Export(ctx context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *InMemoryExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// Spans 已导出的span，需先调用 Provider.ForceFlush
+func (e *InMemoryExporter) Spans() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]SpanData(nil), e.spans...)
+}
+
+// Reset 清空已导出的span
+func (e *InMemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = nil
+}
+
+// OTLPExporter 以 OTLP/HTTP JSON 格式导出到collector
+type OTLPExporter struct {
+	url     string
+	service string
+	headers map[string]string
+	client  *http.Client
+}
+
+// otlpTracesPath OTLP/HTTP 接收span的路径
+const otlpTracesPath = "/v1/traces"
+
+// NewOTLPExporter 创建OTLP导出器，endpoint不带路径时补上 /v1/traces
+func NewOTLPExporter(endpoint, service string, headers map[string]string) *OTLPExporter {
+	url := strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(url, otlpTracesPath) {
+		url += otlpTracesPath
+	}
+	return &OTLPExporter{url: url, service: service, headers: headers, client: &http.Client{Timeout: exportTimeout}}
+}
+
+func (e *OTLPExporter) Export(ctx context.Context, spans []SpanData) error {
+	body, err := json.Marshal(otlpRequest(e.service, spans))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("otlp export status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	e.client.CloseIdleConnections()
+	return nil
+}
+
+// otlpRequest 按 OTLP JSON 编码，ID为十六进制，64位整数为字符串
+func otlpRequest(service string, spans []SpanData) map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(spans))
+	for _, s := range spans {
+		span := map[string]interface{}{
+			"traceId":           s.TraceID.String(),
+			"spanId":            s.SpanID.String(),
+			"name":              s.Name,
+			"kind":              int(s.Kind),
+			"startTimeUnixNano": strconv.FormatInt(s.Start.UnixNano(), 10),
+			"endTimeUnixNano":   strconv.FormatInt(s.End.UnixNano(), 10),
+			"attributes":        otlpAttributes(s.Attributes),
+			"status":            map[string]interface{}{"code": int(s.Status), "message": s.StatusMessage},
+		}
+		if s.ParentSpanID.IsValid() {
+			span["parentSpanId"] = s.ParentSpanID.String()
+		}
+		out = append(out, span)
+	}
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{
+				"attributes": otlpAttributes([]Attribute{Attr("service.name", service)}),
+			},
+			"scopeSpans": []interface{}{map[string]interface{}{
+				"scope": map[string]interface{}{"name": "playGround/trace"},
+				"spans": out,
+			}},
+		}},
+	}
+}
+
+func otlpAttributes(attrs []Attribute) []interface{} {
+	out := make([]interface{}, 0, len(attrs))
+	for _, a := range attrs {
+		var v map[string]interface{}
+		switch x := a.Value.(type) {
+		case string:
+			v = map[string]interface{}{"stringValue": x}
+		case bool:
+			v = map[string]interface{}{"boolValue": x}
+		case int:
+			v = map[string]interface{}{"intValue": strconv.Itoa(x)}
+		case int64:
+			v = map[string]interface{}{"intValue": strconv.FormatInt(x, 10)}
+		case float64:
+			v = map[string]interface{}{"doubleValue": x}
+		default:
+			v = map[string]interface{}{"stringValue": fmt.Sprint(x)}
+		}
+		out = append(out, map[string]interface{}{"key": a.Key, "value": v})
+	}
+	return out
+}
@@ -0,0 +1,55 @@
+package trace
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServer 一元调用的链路拦截器，沿用元数据traceparent中的上游链路
+func UnaryServer(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, span := startGRPC(ctx, info.FullMethod)
+	defer span.End()
+	resp, err := handler(ctx, req)
+	endGRPC(span, err)
+	return resp, err
+}
+
+// StreamServer 流式调用的链路拦截器
+func StreamServer(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, span := startGRPC(ss.Context(), info.FullMethod)
+	defer span.End()
+	err := handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	endGRPC(span, err)
+	return err
+}
+
+func startGRPC(ctx context.Context, method string) (context.Context, *Span) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = Extract(ctx, MetadataCarrier(md))
+	}
+	return Start(ctx, method, KindServer, Attr("rpc.system", "grpc"), Attr("rpc.method", method))
+}
+
+// endGRPC 记录状态码，服务端错误才标记为失败
+func endGRPC(span *Span, err error) {
+	code := status.Code(err)
+	span.SetAttributes(Attr("rpc.grpc.status_code", int(code)))
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		span.SetError(err)
+	}
+}
+
+// wrappedStream 替换流的上下文
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *wrappedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,32 @@
+package trace
+
+import (
+	"net/http"
+	"playGround/middleware"
+)
+
+// HTTP 为每个请求创建服务端span，沿用请求头traceparent中的上游链路，route返回请求匹配的路由模式
+func HTTP(route func(*http.Request) string) middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := Extract(r.Context(), HeaderCarrier(r.Header))
+			pattern := route(r)
+			name := pattern
+			if name == "" {
+				name = "HTTP " + r.Method
+			}
+			ctx, span := Start(ctx, name, KindServer,
+				Attr("http.method", r.Method),
+				Attr("http.route", pattern),
+				Attr("http.target", r.URL.Path),
+			)
+			defer span.End()
+			sw := middleware.NewStatusWriter(w)
+			next.ServeHTTP(sw, r.WithContext(ctx))
+			span.SetAttributes(Attr("http.status_code", sw.Status()))
+			if sw.Status() >= http.StatusInternalServerError {
+				span.SetStatus(StatusError, http.StatusText(sw.Status()))
+			}
+		})
+	}
+}
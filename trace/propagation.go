@@ -0,0 +1,112 @@
+package trace
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// W3C Trace Context 的请求头
+const (
+	traceparentHeader = "traceparent"
+	tracestateHeader  = "tracestate"
+)
+
+// flagSampled traceparent中的采样标记
+const flagSampled = 0x01
+
+// Carrier 读写传递链路信息的请求头
+type Carrier interface {
+	Get(key string) string
+	Set(key, value string)
+}
+
+// HeaderCarrier HTTP请求头
+type HeaderCarrier http.Header
+
+func (c HeaderCarrier) Get(key string) string {
+	return http.Header(c).Get(key)
+}
+
+func (c HeaderCarrier) Set(key, value string) {
+	http.Header(c).Set(key, value)
+}
+
+// MetadataCarrier gRPC元数据
+type MetadataCarrier metadata.MD
+
+func (c MetadataCarrier) Get(key string) string {
+	if vals := metadata.MD(c).Get(key); len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
+func (c MetadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Inject 将上下文中的span写入traceparent和tracestate，供调用下游时使用
+func Inject(ctx context.Context, c Carrier) {
+	sc, ok := parentFromContext(ctx)
+	if !ok {
+		return
+	}
+	c.Set(traceparentHeader, formatTraceparent(sc))
+	if sc.TraceState != "" {
+		c.Set(tracestateHeader, sc.TraceState)
+	}
+}
+
+// Extract 解析traceparent，合法时作为上游span保存到上下文中，否则返回原上下文
+func Extract(ctx context.Context, c Carrier) context.Context {
+	sc, ok := parseTraceparent(c.Get(traceparentHeader))
+	if !ok {
+		return ctx
+	}
+	sc.TraceState = c.Get(tracestateHeader)
+	return ContextWithRemote(ctx, sc)
+}
+
+func formatTraceparent(sc SpanContext) string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return "00-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + flags
+}
+
+// parseTraceparent 解析 version-traceid-spanid-flags，高版本只读取前四个字段
+func parseTraceparent(v string) (sc SpanContext, ok bool) {
+	if len(v) < 55 || v[2] != '-' || v[35] != '-' || v[52] != '-' {
+		return sc, false
+	}
+	version := v[:2]
+	if version == "ff" || !isLowerHex(version) || version == "00" && len(v) != 55 || len(v) > 55 && v[55] != '-' {
+		return sc, false
+	}
+	for _, s := range []string{v[3:35], v[36:52], v[53:55]} {
+		if !isLowerHex(s) {
+			return sc, false
+		}
+	}
+	hex.Decode(sc.TraceID[:], []byte(v[3:35]))
+	hex.Decode(sc.SpanID[:], []byte(v[36:52]))
+	var flags [1]byte
+	hex.Decode(flags[:], []byte(v[53:55]))
+	sc.Sampled = flags[0]&flagSampled != 0
+	sc.Remote = true
+	return sc, sc.IsValid()
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
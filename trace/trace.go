@@ -0,0 +1,343 @@
+package trace
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"playGround/utils"
+	"sync"
+	"time"
+)
+
+// TraceID 链路ID
+type TraceID [16]byte
+
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+// SpanID span的ID
+type SpanID [8]byte
+
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+// SpanKind span的类型，取值同OTLP
+type SpanKind int
+
+const (
+	KindInternal SpanKind = 1
+	KindServer   SpanKind = 2
+	KindClient   SpanKind = 3
+)
+
+// StatusCode span的状态，取值同OTLP
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = 0
+	StatusOK    StatusCode = 1
+	StatusError StatusCode = 2
+)
+
+// SpanContext 在进程间传递的span信息
+type SpanContext struct {
+	TraceID    TraceID
+	SpanID     SpanID
+	Sampled    bool
+	TraceState string //W3C tracestate，原样透传
+	Remote     bool   //从请求头中解析得到
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Attribute span的属性，值为string、int64、float64或bool
+type Attribute struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// Attr 创建属性
+func Attr(key string, value interface{}) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// SpanData 结束后导出的span
+type SpanData struct {
+	Name          string      `json:"name"`
+	Kind          SpanKind    `json:"kind"`
+	TraceID       TraceID     `json:"-"`
+	SpanID        SpanID      `json:"-"`
+	ParentSpanID  SpanID      `json:"-"`
+	Start         time.Time   `json:"start"`
+	End           time.Time   `json:"end"`
+	Attributes    []Attribute `json:"attributes,omitempty"`
+	Status        StatusCode  `json:"status"`
+	StatusMessage string      `json:"status_message,omitempty"`
+}
+
+// Span 一次操作的计时和属性，方法在nil上调用时不做任何事，未启用追踪时调用方无需判空
+type Span struct {
+	p  *Provider
+	sc SpanContext
+
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+// SpanContext 返回span的传递信息
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.sc
+}
+
+// SetAttributes 设置属性
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil || !s.sc.Sampled {
+		return
+	}
+	s.mu.Lock()
+	s.data.Attributes = append(s.data.Attributes, attrs...)
+	s.mu.Unlock()
+}
+
+// SetName 修改span的名称
+func (s *Span) SetName(name string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.data.Name = name
+	s.mu.Unlock()
+}
+
+// SetError 记录错误，err为nil时不做任何事
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	s.data.Status, s.data.StatusMessage = StatusError, err.Error()
+	s.mu.Unlock()
+}
+
+// SetStatus 设置状态
+func (s *Span) SetStatus(code StatusCode, msg string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.data.Status, s.data.StatusMessage = code, msg
+	s.mu.Unlock()
+}
+
+// End 结束span，采样的span交给导出器，重复调用只生效一次
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.mu.Unlock()
+	if s.sc.Sampled {
+		s.p.batcher.enqueue(data)
+	}
+}
+
+type spanKey struct{}
+type remoteKey struct{}
+
+// SpanFromContext 获取上下文中的span，没有时返回nil
+func SpanFromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanKey{}).(*Span)
+	return s
+}
+
+// ContextWithRemote 保存从请求中解析的上游span，作为之后创建的span的父span
+func ContextWithRemote(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// parentFromContext 优先使用进程内的span，其次使用上游传入的span
+func parentFromContext(ctx context.Context) (SpanContext, bool) {
+	if s := SpanFromContext(ctx); s != nil {
+		return s.sc, true
+	}
+	sc, ok := ctx.Value(remoteKey{}).(SpanContext)
+	return sc, ok && sc.IsValid()
+}
+
+// Options 链路追踪的参数
+type Options struct {
+	SampleRatio  float64       //根span的采样比例，大于等于1或不大于0时全部采样，有父span时跟随父span
+	BatchSize    int           //每次导出的最大span数
+	BatchTimeout time.Duration //未攒满一批时的导出间隔
+	QueueSize    int           //等待导出的最大span数，超出时丢弃
+}
+
+// Provider 创建span并异步导出
+type Provider struct {
+	threshold uint64
+	batcher   *batcher
+}
+
+// NewProvider 创建链路追踪
+func NewProvider(exp Exporter, opts Options) *Provider {
+	p := &Provider{threshold: math.MaxUint64}
+	if opts.SampleRatio > 0 && opts.SampleRatio < 1 {
+		p.threshold = uint64(opts.SampleRatio * math.MaxUint64)
+	}
+	p.batcher = newBatcher(exp, opts)
+	return p
+}
+
+// Start 创建span，ctx中有父span时作为其子span，返回的上下文中带有新的span
+func (p *Provider) Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, *Span) {
+	parent, ok := parentFromContext(ctx)
+	s := &Span{p: p}
+	if ok {
+		s.sc = SpanContext{TraceID: parent.TraceID, Sampled: parent.Sampled, TraceState: parent.TraceState}
+		s.data.ParentSpanID = parent.SpanID
+	} else {
+		s.sc = SpanContext{TraceID: newTraceID()}
+		s.sc.Sampled = binary.BigEndian.Uint64(s.sc.TraceID[8:]) <= p.threshold
+	}
+	s.sc.SpanID = newSpanID()
+	s.data.Name, s.data.Kind = name, kind
+	s.data.TraceID, s.data.SpanID = s.sc.TraceID, s.sc.SpanID
+	s.data.Start = time.Now()
+	if s.sc.Sampled {
+		s.data.Attributes = attrs
+	}
+	ctx = context.WithValue(ctx, spanKey{}, s)
+	if !ok || parent.Remote {
+		// 请求的入口span，日志中带上链路ID
+		ctx = utils.ContextWithTraceId(ctx, s.sc.TraceID.String())
+	}
+	return ctx, s
+}
+
+// ForceFlush 立即导出等待中的span
+func (p *Provider) ForceFlush(ctx context.Context) error {
+	return p.batcher.flush(ctx)
+}
+
+// Shutdown 导出剩余的span并关闭导出器
+func (p *Provider) Shutdown(ctx context.Context) error {
+	return p.batcher.shutdown(ctx)
+}
+
+func newTraceID() (id TraceID) {
+	binary.BigEndian.PutUint64(id[:8], rand.Uint64())
+	binary.BigEndian.PutUint64(id[8:], rand.Uint64())
+	return
+}
+
+func newSpanID() (id SpanID) {
+	for !id.IsValid() {
+		binary.BigEndian.PutUint64(id[:], rand.Uint64())
+	}
+	return
+}
+
+// global 全局的链路追踪，未启用时为nil
+var global *Provider
+
+// SetProvider 设置全局的链路追踪并接管utils的埋点，需在启动时调用
+func SetProvider(p *Provider) {
+	global = p
+	if p != nil {
+		utils.SetTracer(utilsTracer{p})
+	} else {
+		utils.SetTracer(nil)
+	}
+}
+
+// Enabled 是否已启用
+func Enabled() bool {
+	return global != nil
+}
+
+// Start 使用全局的链路追踪创建span，未启用时返回原上下文和nil
+func Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, *Span) {
+	if global == nil {
+		return ctx, nil
+	}
+	return global.Start(ctx, name, kind, attrs...)
+}
+
+// utilsTracer 实现 utils.Tracer，utils和model中的span均为访问外部依赖的客户端span
+type utilsTracer struct {
+	p *Provider
+}
+
+func (t utilsTracer) Start(ctx context.Context, name string, attrs ...string) (context.Context, func(error)) {
+	kv := make([]Attribute, 0, len(attrs)/2)
+	for i := 0; i+1 < len(attrs); i += 2 {
+		kv = append(kv, Attr(attrs[i], attrs[i+1]))
+	}
+	ctx, s := t.p.Start(ctx, name, KindClient, kv...)
+	return ctx, func(err error) {
+		s.SetError(err)
+		s.End()
+	}
+}
+
+// Config 链路追踪配置
+type Config struct {
+	Exporter    string            `yaml:"exporter"`     //stdout或otlp，为空时不启用
+	Endpoint    string            `yaml:"endpoint"`     //OTLP/HTTP 的地址，如 http://localhost:4318
+	Headers     map[string]string `yaml:"headers"`      //导出到OTLP时附加的请求头，如鉴权
+	ServiceName string            `yaml:"service_name"` //服务名
+	SampleRatio float64           `yaml:"sample_ratio"` //根span的采样比例，不填时全部采样
+}
+
+// defaultServiceName 未配置服务名时使用
+const defaultServiceName = "playGround"
+
+// Init 按配置创建导出器和全局的链路追踪，未启用时返回nil
+func Init(cfg Config) (*Provider, error) {
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = defaultServiceName
+	}
+	var exp Exporter
+	switch cfg.Exporter {
+	case "":
+		return nil, nil
+	case "stdout":
+		exp = NewStdoutExporter(nil)
+	case "otlp":
+		if cfg.Endpoint == "" {
+			return nil, fmt.Errorf("trace: otlp exporter requires endpoint")
+		}
+		exp = NewOTLPExporter(cfg.Endpoint, cfg.ServiceName, cfg.Headers)
+	default:
+		return nil, fmt.Errorf("trace: unknown exporter %q", cfg.Exporter)
+	}
+	p := NewProvider(exp, Options{SampleRatio: cfg.SampleRatio})
+	SetProvider(p)
+	return p, nil
+}
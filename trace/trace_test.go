@@ -0,0 +1,143 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"playGround/utils"
+	"testing"
+)
+
+const (
+	remoteTraceId = "4bf92f3577b34da6a3ce929d0e0e4736"
+	remoteSpanId  = "00f067aa0ba902b7"
+)
+
+// useProvider 设置全局的链路追踪，测试结束后恢复
+func useProvider(t *testing.T, opts Options) (*Provider, *InMemoryExporter) {
+	t.Helper()
+	exp := NewInMemoryExporter()
+	p := NewProvider(exp, opts)
+	SetProvider(p)
+	t.Cleanup(func() {
+		SetProvider(nil)
+		p.Shutdown(context.Background())
+	})
+	return p, exp
+}
+
+func flush(t *testing.T, p *Provider, exp *InMemoryExporter) []SpanData {
+	t.Helper()
+	if err := p.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return exp.Spans()
+}
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		v       string
+		ok      bool
+		sampled bool
+	}{
+		{"00-" + remoteTraceId + "-" + remoteSpanId + "-01", true, true},
+		{"00-" + remoteTraceId + "-" + remoteSpanId + "-00", true, false},
+		{"01-" + remoteTraceId + "-" + remoteSpanId + "-01-future", true, true},
+		{"00-" + remoteTraceId + "-" + remoteSpanId + "-01-extra", false, false},
+		{"ff-" + remoteTraceId + "-" + remoteSpanId + "-01", false, false},
+		{"00-" + "4BF92F3577B34DA6A3CE929D0E0E4736" + "-" + remoteSpanId + "-01", false, false},
+		{"00-00000000000000000000000000000000-" + remoteSpanId + "-01", false, false},
+		{"00-" + remoteTraceId + "-0000000000000000-01", false, false},
+		{"", false, false},
+	}
+	for _, tt := range tests {
+		sc, ok := parseTraceparent(tt.v)
+		if ok != tt.ok || ok && (sc.Sampled != tt.sampled || sc.TraceID.String() != remoteTraceId || !sc.Remote) {
+			t.Errorf("parseTraceparent(%q) = %+v, %v", tt.v, sc, ok)
+		}
+	}
+}
+
+func TestInjectExtract(t *testing.T) {
+	in := http.Header{}
+	in.Set(traceparentHeader, "00-"+remoteTraceId+"-"+remoteSpanId+"-01")
+	in.Set(tracestateHeader, "vendor=a")
+	ctx := Extract(context.Background(), HeaderCarrier(in))
+
+	out := http.Header{}
+	Inject(ctx, HeaderCarrier(out))
+	if out.Get(traceparentHeader) != in.Get(traceparentHeader) || out.Get(tracestateHeader) != "vendor=a" {
+		t.Fatalf("inject = %v", out)
+	}
+	// 没有链路信息时不写入
+	out = http.Header{}
+	Inject(context.Background(), HeaderCarrier(out))
+	if len(out) != 0 {
+		t.Fatalf("inject without span = %v", out)
+	}
+}
+
+func TestHTTPParentChild(t *testing.T) {
+	p, exp := useProvider(t, Options{})
+	var traceId string
+	h := HTTP(func(r *http.Request) string { return "GET /articles" })(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceId = utils.TraceIdFromContext(r.Context())
+		// 与 model.StartSpan 一样经由utils创建访问Mongo的子span
+		_, end := utils.StartSpan(r.Context(), "mongo find article", "db.system", "mongodb")
+		end(errors.New("boom"))
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/articles", nil)
+	req.Header.Set(traceparentHeader, "00-"+remoteTraceId+"-"+remoteSpanId+"-01")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := flush(t, p, exp)
+	if len(spans) != 2 {
+		t.Fatalf("spans = %d", len(spans))
+	}
+	child, server := spans[0], spans[1]
+	if server.Kind != KindServer || server.Name != "GET /articles" || server.ParentSpanID.String() != remoteSpanId {
+		t.Errorf("server span = %+v", server)
+	}
+	if child.Kind != KindClient || child.ParentSpanID != server.SpanID || child.Status != StatusError {
+		t.Errorf("child span = %+v", child)
+	}
+	for _, s := range spans {
+		if s.TraceID.String() != remoteTraceId {
+			t.Errorf("%s trace id = %s", s.Name, s.TraceID)
+		}
+	}
+	if traceId != remoteTraceId {
+		t.Errorf("log trace id = %q", traceId)
+	}
+}
+
+func TestSampling(t *testing.T) {
+	p, exp := useProvider(t, Options{SampleRatio: 0.25, QueueSize: 10000})
+	const n = 4000
+	for i := 0; i < n; i++ {
+		_, s := Start(context.Background(), "root", KindInternal)
+		s.End()
+	}
+	if got := len(flush(t, p, exp)); got < n/8 || got > n*3/8 {
+		t.Fatalf("sampled %d of %d with ratio 0.25", got, n)
+	}
+
+	// 有父span时跟随父span的采样结果，不再按比例采样
+	exp.Reset()
+	for _, flags := range []string{"00", "01"} {
+		in := http.Header{}
+		in.Set(traceparentHeader, "00-"+remoteTraceId+"-"+remoteSpanId+"-"+flags)
+		ctx, s := Start(Extract(context.Background(), HeaderCarrier(in)), "server", KindServer)
+		_, child := Start(ctx, "child", KindInternal)
+		if child.SpanContext().Sampled != (flags == "01") {
+			t.Errorf("flags %s: child sampled = %v", flags, child.SpanContext().Sampled)
+		}
+		child.End()
+		s.End()
+	}
+	spans := flush(t, p, exp)
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans, want only the sampled trace", len(spans))
+	}
+}
@@ -0,0 +1,93 @@
+package upload
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"playGround/model"
+	"playGround/storage"
+	"playGround/utils"
+	"strings"
+)
+
+/*
+	按内容去重
+*/
+
+// ArchiveSuffix 解压保存的压缩包的文件ID后缀。
+// 压缩包另外保存解压后的目录，与按普通文件保存的相同内容分开记录，避免引用到没有解压目录的记录
+const ArchiveSuffix = "-archive"
+
+// BlobId 文件ID，普通文件为内容的SHA-256，解压保存的压缩包带 ArchiveSuffix
+func BlobId(hash string, extracted bool) string {
+	if extracted {
+		return hash + ArchiveSuffix
+	}
+	return hash
+}
+
+// ValidBlobId 是否为 BlobId 生成的ID，哈希只能是64位小写十六进制
+func ValidBlobId(id string) bool {
+	id = strings.TrimSuffix(id, ArchiveSuffix)
+	if len(id) != sha256.Size*2 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// BlobKey 按文件ID生成存储key，取哈希前两位分目录避免单个目录文件过多
+func BlobKey(blobId, ext string) string {
+	return "blob/" + blobId[:2] + "/" + blobId + ext
+}
+
+// BlobPrefix 压缩包解压后文件的存储目录
+func BlobPrefix(blobId string) string {
+	return BlobKey(blobId, "") + "/"
+}
+
+// Acquire 引用b对应的文件，存储中还没有该文件时调用put写入，失败时撤销引用，
+// 没有其他引用时由 Release 删除已写入的部分，put失败时无需自行清理
+func Acquire(ctx context.Context, s storage.Storage, b *model.Blob, put func(b *model.Blob) error) (*model.Blob, error) {
+	rs, err := b.Acquire()
+	if err != nil {
+		return nil, utils.NewError(utils.ErrStorage, "保存文件信息失败", err)
+	}
+	// 并发上传相同内容时可能都会写入，内容相同，覆盖不影响
+	_, err = s.Stat(ctx, rs.Key)
+	if errors.Is(err, storage.ErrNotExist) {
+		err = put(rs)
+	}
+	if err != nil {
+		if rerr := Release(ctx, s, rs.Id); rerr != nil {
+			utils.WithContext(ctx).WithError(rerr).Warn("release blob err")
+		}
+		return nil, utils.NewError(utils.ErrStorage, "保存文件失败", err)
+	}
+	return rs, nil
+}
+
+// Release 撤销对文件的引用，没有引用时删除存储中的文件
+func Release(ctx context.Context, s storage.Storage, blobId string) error {
+	blob := new(model.Blob)
+	b, err := blob.Release(blobId)
+	if err != nil || b == nil {
+		return err
+	}
+	err = s.Delete(ctx, b.Key)
+	if err == nil && b.Prefix != "" {
+		err = storage.DeletePrefix(ctx, s, b.Prefix)
+	}
+	if err != nil {
+		// 文件删除失败时保留记录，之后上传相同内容可以继续使用
+		if uerr := blob.Unmark(blobId); uerr != nil {
+			utils.WithContext(ctx).WithError(uerr).Warn("unmark blob err")
+		}
+		return err
+	}
+	return blob.Remove(blobId)
+}
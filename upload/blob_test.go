@@ -0,0 +1,27 @@
+package upload
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBlobId(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+	file, archive := BlobId(hash, false), BlobId(hash, true)
+	if file == archive {
+		t.Fatalf("archive and file share blob id %q", file)
+	}
+	if BlobPrefix(file) == BlobPrefix(archive) || BlobKey(file, ".zip") == BlobKey(archive, ".zip") {
+		t.Error("archive and file share storage keys")
+	}
+	for _, id := range []string{file, archive} {
+		if !ValidBlobId(id) {
+			t.Errorf("ValidBlobId(%q) = false", id)
+		}
+	}
+	for _, id := range []string{"", "../x", strings.ToUpper(hash), hash[:63], hash + "-other", ArchiveSuffix} {
+		if ValidBlobId(id) {
+			t.Errorf("ValidBlobId(%q) = true", id)
+		}
+	}
+}
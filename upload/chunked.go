@@ -0,0 +1,406 @@
+package upload
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"playGround/utils"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/*
+	分片上传
+*/
+
+// ErrSessionNotFound 上传任务不存在或已过期
+var ErrSessionNotFound = utils.NewError(utils.ErrNotFound, "上传任务不存在或已过期", nil)
+
+// ErrSessionCompleting 上传任务正在合并，如客户端重试合并请求
+var ErrSessionCompleting = utils.NewError(utils.ErrConflict, "上传任务正在合并，请稍后查询结果", nil)
+
+const (
+	sessionFile   = "session.json"
+	assembledFile = "assembled"
+	chunkExt      = ".part"
+)
+
+// Session 分片上传任务
+type Session struct {
+	Id        string `json:"upload_id"`
+	FileName  string `json:"file_name"`        //原始文件名
+	Name      string `json:"name"`             //上传类别，同 Upload 的name参数
+	Uploader  string `json:"uploader"`         //上传人
+	Size      int64  `json:"size"`             //文件总大小
+	ChunkSize int64  `json:"chunk_size"`       //分片大小，最后一片可以小于该值
+	Chunks    int    `json:"chunks"`           //分片总数
+	Sha256    string `json:"sha256,omitempty"` //整个文件的SHA-256，为空时不校验
+	Received  []int  `json:"received"`         //已收到的分片序号，从0开始
+	CreatedAt int64  `json:"created_at"`
+	ExpiresAt int64  `json:"expires_at"` //超过该时间未完成的任务会被清理
+}
+
+// chunkLen 第index个分片应有的大小
+func (s *Session) chunkLen(index int) int64 {
+	if index == s.Chunks-1 {
+		return s.Size - int64(index)*s.ChunkSize
+	}
+	return s.ChunkSize
+}
+
+// Manager 分片上传任务管理，任务和分片保存在dir目录下，重启后可继续上传
+type Manager struct {
+	mu           sync.Mutex
+	completing   map[string]bool //已合并、调用方还在处理的任务，期间不能再次合并或被清理
+	dir          string
+	ttl          time.Duration
+	maxSize      int64
+	maxChunkSize int64
+}
+
+// NewManager 创建分片上传管理，ttl为任务无活动后的过期时间
+func NewManager(dir string, ttl time.Duration, maxSize, maxChunkSize int64) *Manager {
+	return &Manager{completing: map[string]bool{}, dir: dir, ttl: ttl, maxSize: maxSize, maxChunkSize: maxChunkSize}
+}
+
+// sessionDir 任务目录，id只允许ObjectID格式，避免拼出任意路径
+func (m *Manager) sessionDir(id string) (string, error) {
+	if !primitive.IsValidObjectID(id) {
+		return "", ErrSessionNotFound
+	}
+	return filepath.Join(m.dir, id), nil
+}
+
+// Initiate 创建上传任务
+func (m *Manager) Initiate(fileName, name, uploader string, size, chunkSize int64, checksum string) (*Session, error) {
+	if fileName == "" {
+		return nil, utils.NewError(utils.ErrInvalidParam, "缺少文件名", nil)
+	}
+	if size <= 0 || chunkSize <= 0 {
+		return nil, utils.NewError(utils.ErrInvalidParam, "文件大小或分片大小错误", nil)
+	}
+	if m.maxSize > 0 && size > m.maxSize {
+		return nil, utils.NewError(utils.ErrTooLarge, "上传文件过大", fmt.Errorf("size %d", size))
+	}
+	if m.maxChunkSize > 0 && chunkSize > m.maxChunkSize {
+		return nil, utils.NewError(utils.ErrTooLarge, "分片过大", fmt.Errorf("chunk size %d", chunkSize))
+	}
+	if checksum != "" && !validSha256(checksum) {
+		return nil, utils.NewError(utils.ErrInvalidParam, "文件校验值格式错误", nil)
+	}
+	now := time.Now()
+	s := &Session{
+		Id:        primitive.NewObjectID().Hex(),
+		FileName:  filepath.Base(fileName),
+		Name:      name,
+		Uploader:  uploader,
+		Size:      size,
+		ChunkSize: chunkSize,
+		Chunks:    int((size + chunkSize - 1) / chunkSize),
+		Sha256:    strings.ToLower(checksum),
+		Received:  []int{},
+		CreatedAt: now.Unix(),
+		ExpiresAt: now.Add(m.ttl).Unix(),
+	}
+	dir := filepath.Join(m.dir, s.Id)
+	if err := utils.IsFolder(dir); err != nil {
+		return nil, utils.NewError(utils.ErrStorage, "创建上传任务失败", err)
+	}
+	if err := m.save(dir, s); err != nil {
+		os.RemoveAll(dir)
+		return nil, utils.NewError(utils.ErrStorage, "创建上传任务失败", err)
+	}
+	return s, nil
+}
+
+// PutChunk 保存一个分片，checksum为分片内容的SHA-256，重复上传同一分片会覆盖
+func (m *Manager) PutChunk(id string, index int, r io.Reader, checksum string) (*Session, error) {
+	s, dir, err := m.load(id)
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= s.Chunks {
+		return nil, utils.NewError(utils.ErrInvalidParam, "分片序号错误", fmt.Errorf("index %d of %d", index, s.Chunks))
+	}
+	if !validSha256(checksum) {
+		return nil, utils.NewError(utils.ErrInvalidParam, "缺少分片校验值", nil)
+	}
+	want := s.chunkLen(index)
+	tmp, err := os.CreateTemp(dir, "chunk-*")
+	if err != nil {
+		return nil, utils.NewError(utils.ErrStorage, "保存分片失败", err)
+	}
+	defer os.Remove(tmp.Name())
+	h := sha256.New()
+	// 多读一个字节用于判断分片是否超长
+	n, err := io.Copy(io.MultiWriter(tmp, h), io.LimitReader(r, want+1))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, utils.NewError(utils.ErrStorage, "保存分片失败", err)
+	}
+	if n != want {
+		return nil, utils.NewError(utils.ErrInvalidParam, "分片大小错误", fmt.Errorf("chunk %d: got %d bytes, want %d", index, n, want))
+	}
+	if hex.EncodeToString(h.Sum(nil)) != strings.ToLower(checksum) {
+		return nil, utils.NewError(utils.ErrInvalidParam, "分片校验失败", fmt.Errorf("chunk %d", index))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// 合并期间不能替换分片
+	if m.completing[id] {
+		return nil, ErrSessionCompleting
+	}
+	// 写入期间任务可能已被取消或完成
+	if _, err = os.Stat(filepath.Join(dir, sessionFile)); err != nil {
+		return nil, ErrSessionNotFound
+	}
+	if err = os.Rename(tmp.Name(), filepath.Join(dir, strconv.Itoa(index)+chunkExt)); err != nil {
+		return nil, utils.NewError(utils.ErrStorage, "保存分片失败", err)
+	}
+	// 有分片上传时顺延过期时间
+	s.ExpiresAt = time.Now().Add(m.ttl).Unix()
+	if err = m.save(dir, s); err != nil {
+		return nil, utils.NewError(utils.ErrStorage, "保存分片失败", err)
+	}
+	s.Received, err = received(dir)
+	return s, err
+}
+
+// Get 查询上传任务及已收到的分片
+func (m *Manager) Get(id string) (*Session, error) {
+	s, dir, err := m.load(id)
+	if err != nil {
+		return nil, err
+	}
+	s.Received, err = received(dir)
+	return s, err
+}
+
+// Complete 按顺序合并所有分片并校验，返回合并后的文件路径，返回的任务中Sha256为实际计算的值。
+// 成功后任务标记为合并中，再次合并返回 ErrSessionCompleting，处理完后需调用 Remove，保留任务时调用 Release
+func (m *Manager) Complete(id string) (*Session, string, error) {
+	m.mu.Lock()
+	if m.completing[id] {
+		m.mu.Unlock()
+		return nil, "", ErrSessionCompleting
+	}
+	// 先标记再合并，合并期间不持有锁，其他任务不受影响
+	m.completing[id] = true
+	m.mu.Unlock()
+	s, dst, err := m.complete(id)
+	if err != nil {
+		m.Release(id)
+	}
+	return s, dst, err
+}
+
+// complete 合并分片，调用方需先标记任务为合并中
+func (m *Manager) complete(id string) (*Session, string, error) {
+	s, dir, err := m.load(id)
+	if err != nil {
+		return nil, "", err
+	}
+	if s.Received, err = received(dir); err != nil {
+		return nil, "", err
+	}
+	if len(s.Received) != s.Chunks {
+		return nil, "", utils.NewError(utils.ErrInvalidParam, "分片未上传完整", fmt.Errorf("received %d of %d", len(s.Received), s.Chunks))
+	}
+	dst := filepath.Join(dir, assembledFile)
+	f, err := os.Create(dst)
+	if err != nil {
+		return nil, "", utils.NewError(utils.ErrStorage, "合并分片失败", err)
+	}
+	h := sha256.New()
+	var size int64
+	for i := 0; i < s.Chunks && err == nil; i++ {
+		var n int64
+		n, err = copyFile(io.MultiWriter(f, h), filepath.Join(dir, strconv.Itoa(i)+chunkExt))
+		size += n
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(dst)
+		return nil, "", utils.NewError(utils.ErrStorage, "合并分片失败", err)
+	}
+	if size != s.Size {
+		os.Remove(dst)
+		return nil, "", utils.NewError(utils.ErrInvalidParam, "文件大小不符", fmt.Errorf("got %d bytes, want %d", size, s.Size))
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	if s.Sha256 != "" && sum != s.Sha256 {
+		os.Remove(dst)
+		return nil, "", utils.NewError(utils.ErrInvalidParam, "文件校验失败", nil)
+	}
+	s.Sha256 = sum
+	return s, dst, nil
+}
+
+// Abort 取消上传任务并删除已上传的分片
+func (m *Manager) Abort(id string) error {
+	if _, _, err := m.load(id); err != nil {
+		return err
+	}
+	return m.Remove(id)
+}
+
+// Release 取消合并中的标记，保留任务和分片，客户端可以重新合并
+func (m *Manager) Release(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.completing, id)
+}
+
+// Remove 删除任务目录
+func (m *Manager) Remove(id string) error {
+	dir, err := m.sessionDir(id)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.completing, id)
+	return os.RemoveAll(dir)
+}
+
+// Cleanup 删除已过期的上传任务，返回删除的数量
+func (m *Manager) Cleanup() (int, error) {
+	entries, err := os.ReadDir(m.dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now().Unix()
+	count := 0
+	for _, e := range entries {
+		if !e.IsDir() || !primitive.IsValidObjectID(e.Name()) {
+			continue
+		}
+		m.mu.Lock()
+		completing := m.completing[e.Name()]
+		m.mu.Unlock()
+		if completing {
+			continue
+		}
+		s, _, _ := m.load(e.Name())
+		if s == nil {
+			// 没有任务信息或任务信息无法解析的目录是创建失败或写坏的残留，按修改时间清理
+			info, ierr := e.Info()
+			if ierr != nil || time.Since(info.ModTime()) < m.ttl {
+				continue
+			}
+		} else if s.ExpiresAt > now {
+			continue
+		}
+		if err = m.Remove(e.Name()); err != nil {
+			utils.Warnf("remove expired upload %s err: %v", e.Name(), err)
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Run 定期清理过期任务，直到ctx结束
+func (m *Manager) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := m.Cleanup(); err != nil {
+				utils.Errorf("cleanup chunked uploads err: %v", err)
+			} else if n > 0 {
+				utils.Infof("cleanup %d expired chunked uploads", n)
+			}
+		}
+	}
+}
+
+// load 读取任务信息，已过期的任务视为不存在
+func (m *Manager) load(id string) (*Session, string, error) {
+	dir, err := m.sessionDir(id)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, sessionFile))
+	if os.IsNotExist(err) {
+		return nil, "", ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, "", utils.NewError(utils.ErrStorage, "读取上传任务失败", err)
+	}
+	s := new(Session)
+	if err = json.Unmarshal(data, s); err != nil {
+		return nil, "", utils.NewError(utils.ErrStorage, "读取上传任务失败", err)
+	}
+	if s.ExpiresAt <= time.Now().Unix() {
+		return s, dir, ErrSessionNotFound
+	}
+	return s, dir, nil
+}
+
+// save 写入任务信息
+func (m *Manager) save(dir string, s *Session) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, sessionFile+".tmp")
+	if err = os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, sessionFile))
+}
+
+// received 已收到的分片序号
+func received(dir string) ([]int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, utils.NewError(utils.ErrStorage, "读取上传任务失败", err)
+	}
+	rs := []int{}
+	for _, e := range entries {
+		if index, err := strconv.Atoi(strings.TrimSuffix(e.Name(), chunkExt)); err == nil && strings.HasSuffix(e.Name(), chunkExt) {
+			rs = append(rs, index)
+		}
+	}
+	sort.Ints(rs)
+	return rs, nil
+}
+
+func copyFile(w io.Writer, fpath string) (int64, error) {
+	f, err := os.Open(fpath)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return io.Copy(w, f)
+}
+
+func validSha256(s string) bool {
+	if len(s) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
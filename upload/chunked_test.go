@@ -0,0 +1,164 @@
+package upload
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"playGround/utils"
+	"testing"
+	"time"
+)
+
+func sum(b []byte) string {
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+// newSession 创建任务并上传全部分片
+func newSession(t *testing.T, m *Manager, content []byte, chunkSize int) *Session {
+	t.Helper()
+	s, err := m.Initiate("a.txt", "office", "u1", int64(len(content)), int64(chunkSize), sum(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i*chunkSize < len(content); i++ {
+		chunk := content[i*chunkSize : min((i+1)*chunkSize, len(content))]
+		if _, err = m.PutChunk(s.Id, i, bytes.NewReader(chunk), sum(chunk)); err != nil {
+			t.Fatalf("put chunk %d: %v", i, err)
+		}
+	}
+	return s
+}
+
+func TestChunkedComplete(t *testing.T) {
+	m := NewManager(t.TempDir(), time.Hour, 0, 0)
+	content := bytes.Repeat([]byte("0123456789"), 10)
+	s := newSession(t, m, content, 30)
+	rs, fpath, err := m.Complete(s.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(fpath)
+	if !bytes.Equal(data, content) || rs.Sha256 != sum(content) {
+		t.Fatalf("assembled %d bytes, sha256 %s", len(data), rs.Sha256)
+	}
+	if err = m.Remove(s.Id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = m.Get(s.Id); err != ErrSessionNotFound {
+		t.Fatalf("get after remove: %v", err)
+	}
+}
+
+func TestChunkedCompleteTwice(t *testing.T) {
+	m := NewManager(t.TempDir(), time.Hour, 0, 0)
+	content := []byte("hello world")
+	s := newSession(t, m, content, 4)
+	_, fpath, err := m.Complete(s.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 第一次合并的结果还在处理中，重试不能截断合并后的文件
+	if _, _, err = m.Complete(s.Id); utils.ErrorKind(err) != utils.ErrConflict {
+		t.Fatalf("second complete: %v", err)
+	}
+	if data, _ := os.ReadFile(fpath); !bytes.Equal(data, content) {
+		t.Fatalf("assembled file changed: %q", data)
+	}
+	if n, _ := m.Cleanup(); n != 0 {
+		t.Fatalf("cleanup removed completing session")
+	}
+	m.Release(s.Id)
+	if _, _, err = m.Complete(s.Id); err != nil {
+		t.Fatalf("complete after release: %v", err)
+	}
+}
+
+func TestChunkedCompleteFailed(t *testing.T) {
+	m := NewManager(t.TempDir(), time.Hour, 0, 0)
+	content := []byte("hello world")
+	s, err := m.Initiate("a.txt", "office", "u1", int64(len(content)), 8, sum(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = m.PutChunk(s.Id, 0, bytes.NewReader(content[:8]), sum(content[:8])); err != nil {
+		t.Fatal(err)
+	}
+	// 合并失败后取消标记，补齐分片可以再次合并
+	if _, _, err = m.Complete(s.Id); utils.ErrorKind(err) != utils.ErrInvalidParam {
+		t.Fatalf("complete incomplete: %v", err)
+	}
+	if _, err = m.PutChunk(s.Id, 1, bytes.NewReader(content[8:]), sum(content[8:])); err != nil {
+		t.Fatalf("put after failed complete: %v", err)
+	}
+	if _, _, err = m.Complete(s.Id); err != nil {
+		t.Fatal(err)
+	}
+	// 合并中的任务不能替换分片
+	if _, err = m.PutChunk(s.Id, 1, bytes.NewReader(content[8:]), sum(content[8:])); utils.ErrorKind(err) != utils.ErrConflict {
+		t.Fatalf("put while completing: %v", err)
+	}
+}
+
+func TestChunkedRejects(t *testing.T) {
+	m := NewManager(t.TempDir(), time.Hour, 100, 0)
+	if _, err := m.Initiate("a.txt", "", "", 101, 10, ""); utils.ErrorKind(err) != utils.ErrTooLarge {
+		t.Errorf("too large: %v", err)
+	}
+	s, err := m.Initiate("a.txt", "", "", 8, 4, sum([]byte("abcdefgh")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = m.PutChunk(s.Id, 0, bytes.NewReader([]byte("abcd")), sum([]byte("xxxx"))); utils.ErrorKind(err) != utils.ErrInvalidParam {
+		t.Errorf("bad checksum: %v", err)
+	}
+	if _, err = m.PutChunk(s.Id, 0, bytes.NewReader([]byte("abcde")), sum([]byte("abcde"))); utils.ErrorKind(err) != utils.ErrInvalidParam {
+		t.Errorf("oversized chunk: %v", err)
+	}
+	if _, err = m.PutChunk(s.Id, 2, bytes.NewReader([]byte("abcd")), sum([]byte("abcd"))); utils.ErrorKind(err) != utils.ErrInvalidParam {
+		t.Errorf("bad index: %v", err)
+	}
+	if _, _, err = m.Complete(s.Id); utils.ErrorKind(err) != utils.ErrInvalidParam {
+		t.Errorf("incomplete: %v", err)
+	}
+	m.PutChunk(s.Id, 0, bytes.NewReader([]byte("abcd")), sum([]byte("abcd")))
+	m.PutChunk(s.Id, 1, bytes.NewReader([]byte("zzzz")), sum([]byte("zzzz")))
+	if _, _, err = m.Complete(s.Id); utils.ErrorKind(err) != utils.ErrInvalidParam {
+		t.Errorf("file checksum: %v", err)
+	}
+	if _, err = m.Get("../" + s.Id); err != ErrSessionNotFound {
+		t.Errorf("path traversal: %v", err)
+	}
+}
+
+func TestChunkedCleanup(t *testing.T) {
+	dir := t.TempDir()
+	m := NewManager(dir, time.Hour, 0, 0)
+	live := newSession(t, m, []byte("live"), 4)
+	expired := newSession(t, m, []byte("expired"), 4)
+	corrupt := newSession(t, m, []byte("corrupt"), 4)
+	fresh := newSession(t, m, []byte("fresh"), 4)
+
+	// 过期的任务
+	m.ttl = -time.Hour
+	m.PutChunk(expired.Id, 0, bytes.NewReader([]byte("expi")), sum([]byte("expi")))
+	m.ttl = time.Hour
+	// 任务信息写坏的目录，超过有效期的才删除
+	old := time.Now().Add(-2 * time.Hour)
+	for _, id := range []string{corrupt.Id, fresh.Id} {
+		os.WriteFile(filepath.Join(dir, id, sessionFile), []byte("{"), 0644)
+	}
+	os.Chtimes(filepath.Join(dir, corrupt.Id), old, old)
+
+	n, err := m.Cleanup()
+	if err != nil || n != 2 {
+		t.Fatalf("cleanup = %d, %v", n, err)
+	}
+	for id, want := range map[string]bool{live.Id: true, expired.Id: false, corrupt.Id: false, fresh.Id: true} {
+		if _, err := os.Stat(filepath.Join(dir, id)); (err == nil) != want {
+			t.Errorf("%s exists = %v, want %v", id, err == nil, want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"playGround/utils"
+	"testing"
+)
+
+// multipartRequest 按顺序写入表单字段，file不为nil时写入文件
+func multipartRequest(t *testing.T, fields map[string]string, file []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for k, v := range fields {
+		mw.WriteField(k, v)
+	}
+	if file != nil {
+		fw, err := mw.CreateFormFile("file", "../dir/report.csv")
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write(file)
+	}
+	mw.Close()
+	r := httptest.NewRequest(http.MethodPost, "/upload", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	return r
+}
+
+func TestReadUploadForm(t *testing.T) {
+	content := bytes.Repeat([]byte("a,b,c\n"), 1000)
+	form, err := readUploadForm(multipartRequest(t, map[string]string{"name": "office", "uploader": "u1"}, content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer form.Close()
+	sum := sha256.Sum256(content)
+	if form.hash != hex.EncodeToString(sum[:]) || form.size != int64(len(content)) {
+		t.Errorf("hash %s size %d", form.hash, form.size)
+	}
+	if form.fileName != "report.csv" || form.values["name"] != "office" || form.values["uploader"] != "u1" {
+		t.Errorf("form = %+v", form)
+	}
+	data, err := io.ReadAll(form.file)
+	if err != nil || !bytes.Equal(data, content) {
+		t.Errorf("file content mismatch: %v", err)
+	}
+	tmp := form.file.Name()
+	form.Close()
+	if _, err = os.Stat(tmp); !os.IsNotExist(err) {
+		t.Errorf("temp file not removed: %v", err)
+	}
+}
+
+func TestReadUploadFormErrors(t *testing.T) {
+	_, err := readUploadForm(multipartRequest(t, map[string]string{"name": "office"}, nil))
+	if utils.ErrorKind(formFileError(err)) != utils.ErrInvalidParam {
+		t.Errorf("missing file: %v", err)
+	}
+	r := multipartRequest(t, nil, bytes.Repeat([]byte("x"), 4096))
+	r.Body = http.MaxBytesReader(httptest.NewRecorder(), r.Body, 1024)
+	_, err = readUploadForm(r)
+	if utils.ErrorKind(formFileError(err)) != utils.ErrTooLarge {
+		t.Errorf("too large: %v", err)
+	}
+}
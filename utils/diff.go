@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffOp 差异中一行的类型
+type DiffOp byte
+
+const (
+	DiffEqual  DiffOp = ' '
+	DiffDelete DiffOp = '-'
+	DiffInsert DiffOp = '+'
+)
+
+// DiffLine 差异中的一行
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// maxDiffEdits 按行比较时最多计算的修改次数，超出时中间部分整体替换，避免大段改写时占用过多内存
+const maxDiffEdits = 1000
+
+// LineDiff 按行比较a和b，返回把a改为b的最短编辑序列
+func LineDiff(a, b string) []DiffLine {
+	x, y := splitLines(a), splitLines(b)
+	// 去掉相同的首尾，只比较中间修改过的部分
+	pre := 0
+	for pre < len(x) && pre < len(y) && x[pre] == y[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(x)-pre && suf < len(y)-pre && x[len(x)-1-suf] == y[len(y)-1-suf] {
+		suf++
+	}
+	out := make([]DiffLine, 0, len(x)+len(y)-pre-suf)
+	for _, s := range x[:pre] {
+		out = append(out, DiffLine{DiffEqual, s})
+	}
+	out = append(out, myers(x[pre:len(x)-suf], y[pre:len(y)-suf])...)
+	for _, s := range x[len(x)-suf:] {
+		out = append(out, DiffLine{DiffEqual, s})
+	}
+	return out
+}
+
+// splitLines 按换行拆分，末尾的换行不产生空行
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// myers Myers差分算法，trace只保存每一步用到的对角线
+func myers(x, y []string) []DiffLine {
+	n, m := len(x), len(y)
+	if n == 0 && m == 0 {
+		return nil
+	}
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+	for d := 0; d <= n+m && d <= maxDiffEdits; d++ {
+		for k := -d; k <= d; k += 2 {
+			var i int
+			if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+				i = v[offset+k+1]
+			} else {
+				i = v[offset+k-1] + 1
+			}
+			j := i - k
+			for i < n && j < m && x[i] == y[j] {
+				i++
+				j++
+			}
+			v[offset+k] = i
+		}
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		if k := n - m; k >= -d && k <= d && v[offset+k] >= n {
+			return backtrack(trace, x, y)
+		}
+	}
+	out := make([]DiffLine, 0, n+m)
+	for _, s := range x {
+		out = append(out, DiffLine{DiffDelete, s})
+	}
+	for _, s := range y {
+		out = append(out, DiffLine{DiffInsert, s})
+	}
+	return out
+}
+
+// backtrack 从终点沿trace倒推出编辑序列
+func backtrack(trace [][]int, x, y []string) []DiffLine {
+	i, j := len(x), len(y)
+	var out []DiffLine
+	for d := len(trace) - 1; d > 0; d-- {
+		k := i - j
+		// trace[d-1]保存对角线-(d-1)到d-1
+		prev := func(k int) int { return trace[d-1][k+d-1] }
+		prevK := k - 1
+		if k == -d || k != d && prev(k-1) < prev(k+1) {
+			prevK = k + 1
+		}
+		prevI := prev(prevK)
+		prevJ := prevI - prevK
+		for i > prevI && j > prevJ {
+			out = append(out, DiffLine{DiffEqual, x[i-1]})
+			i--
+			j--
+		}
+		if prevK == k+1 {
+			out = append(out, DiffLine{DiffInsert, y[j-1]})
+			j--
+		} else {
+			out = append(out, DiffLine{DiffDelete, x[i-1]})
+			i--
+		}
+	}
+	for i > 0 {
+		out = append(out, DiffLine{DiffEqual, x[i-1]})
+		i--
+	}
+	for l, r := 0, len(out)-1; l < r; l, r = l+1, r-1 {
+		out[l], out[r] = out[r], out[l]
+	}
+	return out
+}
+
+// UnifiedDiff 输出unified格式的差异，context为每处修改前后保留的相同行数，没有差异时返回空字符串
+func UnifiedDiff(fromName, toName, a, b string, context int) (diff string, added, deleted int) {
+	lines := LineDiff(a, b)
+	var changes []int
+	for i, l := range lines {
+		switch l.Op {
+		case DiffInsert:
+			added++
+			changes = append(changes, i)
+		case DiffDelete:
+			deleted++
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return "", 0, 0
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+	// aLine、bLine为lines[pos]之前两边各自的行数
+	pos, aLine, bLine := 0, 0, 0
+	for c := 0; c < len(changes); {
+		// 间隔不超过2*context行的修改合并为一段
+		last := c
+		for last+1 < len(changes) && changes[last+1]-changes[last] <= 2*context+1 {
+			last++
+		}
+		start, end := max(changes[c]-context, 0), min(changes[last]+context+1, len(lines))
+		for ; pos < start; pos++ {
+			aLine, bLine = aLine+1, bLine+1
+		}
+		aCount, bCount := 0, 0
+		for _, l := range lines[start:end] {
+			if l.Op != DiffInsert {
+				aCount++
+			}
+			if l.Op != DiffDelete {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aLine, aCount), hunkRange(bLine, bCount))
+		for _, l := range lines[start:end] {
+			sb.WriteByte(byte(l.Op))
+			sb.WriteString(l.Text)
+			sb.WriteByte('\n')
+		}
+		aLine, bLine, pos = aLine+aCount, bLine+bCount, end
+		c = last + 1
+	}
+	return sb.String(), added, deleted
+}
+
+// hunkRange 段的起始行和行数，行数为1时省略，为0时起始行为前一行
+func hunkRange(before, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", before)
+	case 1:
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
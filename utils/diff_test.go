@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+// applyDiff 按编辑序列还原两边的内容
+func applyDiff(lines []DiffLine) (a, b []string) {
+	for _, l := range lines {
+		if l.Op != DiffInsert {
+			a = append(a, l.Text)
+		}
+		if l.Op != DiffDelete {
+			b = append(b, l.Text)
+		}
+	}
+	return
+}
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  string
+		edits int
+	}{
+		{"equal", "a\nb\nc\n", "a\nb\nc\n", 0},
+		{"empty to text", "", "a\nb\n", 2},
+		{"text to empty", "a\nb\n", "", 2},
+		{"insert middle", "a\nc\n", "a\nb\nc\n", 1},
+		{"replace line", "a\nb\nc\n", "a\nx\nc\n", 2},
+		{"move", "a\nb\nc\nd\n", "b\nc\nd\na\n", 2},
+		{"no trailing newline", "a\nb", "a\nb\n", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := LineDiff(tt.a, tt.b)
+			a, b := applyDiff(lines)
+			if strings.Join(a, "\n") != strings.Join(splitLines(tt.a), "\n") || strings.Join(b, "\n") != strings.Join(splitLines(tt.b), "\n") {
+				t.Fatalf("diff does not reproduce input: %v", lines)
+			}
+			edits := 0
+			for _, l := range lines {
+				if l.Op != DiffEqual {
+					edits++
+				}
+			}
+			if edits != tt.edits {
+				t.Fatalf("edits = %d, want %d: %v", edits, tt.edits, lines)
+			}
+		})
+	}
+}
+
+func TestLineDiffTooManyEdits(t *testing.T) {
+	var a, b strings.Builder
+	for i := 0; i < maxDiffEdits; i++ {
+		a.WriteString("a\n")
+		b.WriteString("b\n")
+	}
+	lines := LineDiff(a.String(), b.String())
+	x, y := applyDiff(lines)
+	if len(x) != maxDiffEdits || len(y) != maxDiffEdits || lines[0].Op != DiffDelete {
+		t.Fatalf("fallback diff: %d deleted, %d inserted", len(x), len(y))
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n"
+	diff, added, deleted := UnifiedDiff("v1", "v2", a, b, 1)
+	want := "--- v1\n+++ v2\n" +
+		"@@ -2,3 +2,3 @@\n 2\n-3\n+three\n 4\n" +
+		"@@ -10 +10,2 @@\n 10\n+11\n"
+	if diff != want || added != 2 || deleted != 1 {
+		t.Fatalf("diff = %q, +%d -%d", diff, added, deleted)
+	}
+	if diff, _, _ = UnifiedDiff("v1", "v2", a, a, 3); diff != "" {
+		t.Fatalf("equal diff = %q", diff)
+	}
+	// 新增全部内容时原文件的起始行为0
+	if diff, _, _ = UnifiedDiff("v1", "v2", "", "x\n", 3); diff != "--- v1\n+++ v2\n@@ -0,0 +1 @@\n+x\n" {
+		t.Fatalf("create diff = %q", diff)
+	}
+}
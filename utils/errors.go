@@ -0,0 +1,249 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+/*
+	错误处理
+*/
+
+// 错误类别，通过 errors.Is 判断
+var (
+	ErrInvalidParam    = errors.New("invalid_param")
+	ErrNotFound        = errors.New("not_found")
+	ErrTooLarge        = errors.New("too_large")
+	ErrUnsupportedType = errors.New("unsupported_type")
+	ErrStorage         = errors.New("storage_error")
+	ErrConflict        = errors.New("conflict")
+	ErrInternal        = errors.New("internal")
+)
+
+// Error 带类别的业务错误，Msg返回给客户端，Err只用于日志
+type Error struct {
+	Kind error
+	Msg  string
+	Err  error
+}
+
+// NewError 创建业务错误
+func NewError(kind error, msg string, err error) *Error {
+	return &Error{Kind: kind, Msg: msg, Err: err}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Msg + ": " + e.Err.Error()
+	}
+	return e.Msg
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// ErrorKind 返回错误所属类别，无法识别的错误归为 ErrInternal
+func ErrorKind(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return ErrTooLarge
+	}
+	for _, kind := range []error{ErrInvalidParam, ErrNotFound, ErrTooLarge, ErrUnsupportedType, ErrStorage, ErrConflict} {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return ErrInternal
+}
+
+// HTTPStatus 错误对应的HTTP状态码
+func HTTPStatus(err error) int {
+	if s, ok := grpcStatus(err); ok {
+		return HTTPStatusFromCode(s.Code())
+	}
+	switch ErrorKind(err) {
+	case ErrInvalidParam:
+		return http.StatusBadRequest
+	case ErrNotFound:
+		return http.StatusNotFound
+	case ErrTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case ErrUnsupportedType:
+		return http.StatusUnsupportedMediaType
+	case ErrConflict:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GRPCCode 错误对应的gRPC状态码
+func GRPCCode(err error) codes.Code {
+	if s, ok := grpcStatus(err); ok {
+		return s.Code()
+	}
+	switch ErrorKind(err) {
+	case ErrInvalidParam, ErrUnsupportedType:
+		return codes.InvalidArgument
+	case ErrNotFound:
+		return codes.NotFound
+	case ErrTooLarge:
+		return codes.ResourceExhausted
+	case ErrStorage:
+		return codes.Unavailable
+	case ErrConflict:
+		return codes.Aborted
+	default:
+		return codes.Internal
+	}
+}
+
+// GRPCError 将业务错误转换为gRPC错误，已是gRPC错误时原样返回，内部错误不向客户端暴露详情
+func GRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := grpcStatus(err); ok {
+		return err
+	}
+	code := GRPCCode(err)
+	msg := code.String()
+	var e *Error
+	if errors.As(err, &e) {
+		msg = e.Msg
+	}
+	return status.Error(code, msg)
+}
+
+// grpcStatus 取出错误中的gRPC状态
+func grpcStatus(err error) (*status.Status, bool) {
+	var se interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &se) {
+		return nil, false
+	}
+	return se.GRPCStatus(), true
+}
+
+// HTTPStatusFromCode gRPC状态码对应的HTTP状态码
+func HTTPStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// grpcErrorName gRPC状态码在错误响应中的名称，与业务错误类别一致的使用类别名称
+func grpcErrorName(code codes.Code) string {
+	switch code {
+	case codes.InvalidArgument:
+		return ErrInvalidParam.Error()
+	case codes.NotFound:
+		return ErrNotFound.Error()
+	case codes.Aborted:
+		return ErrConflict.Error()
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		return ErrInternal.Error()
+	}
+	// AlreadyExists 转为 already_exists
+	var b strings.Builder
+	for i, c := range code.String() {
+		if unicode.IsUpper(c) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			c = unicode.ToLower(c)
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// Violation 字段的校验错误
+type Violation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// ErrorResp 统一的错误响应
+type ErrorResp struct {
+	Code       int               `json:"code"`
+	Error      string            `json:"error"`
+	Message    string            `json:"message"`
+	Violations []Violation       `json:"violations,omitempty"` //参数校验不通过的字段
+	Reason     string            `json:"reason,omitempty"`     //错误原因，如 VERSION_CONFLICT
+	Metadata   map[string]string `json:"metadata,omitempty"`   //错误的附加信息，如冲突时的当前版本
+	RequestId  string            `json:"request_id,omitempty"`
+}
+
+// setDetails 将gRPC错误详情中的字段错误和错误信息写入响应
+func (resp *ErrorResp) setDetails(s *status.Status) {
+	for _, d := range s.Details() {
+		switch d := d.(type) {
+		case *errdetails.BadRequest:
+			for _, fv := range d.GetFieldViolations() {
+				resp.Violations = append(resp.Violations, Violation{Field: fv.GetField(), Description: fv.GetDescription()})
+			}
+		case *errdetails.ErrorInfo:
+			resp.Reason, resp.Metadata = d.GetReason(), d.GetMetadata()
+		}
+	}
+}
+
+// HTTPError 记录错误日志并以统一格式返回错误
+func HTTPError(w http.ResponseWriter, r *http.Request, err error) {
+	code := HTTPStatus(err)
+	kind := ErrorKind(err)
+	resp := &ErrorResp{
+		Code:      code,
+		Error:     kind.Error(),
+		Message:   http.StatusText(code),
+		RequestId: RequestIdFromContext(r.Context()),
+	}
+	var e *Error
+	if s, ok := grpcStatus(err); ok {
+		resp.Error = grpcErrorName(s.Code())
+		resp.Message = s.Message()
+		resp.setDetails(s)
+	} else if errors.As(err, &e) {
+		resp.Message = e.Msg
+	}
+	logger := WithContext(r.Context()).WithError(err).WithField("status", code)
+	if code >= http.StatusInternalServerError {
+		logger.Error("request failed")
+	} else {
+		logger.Warn("request rejected")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	data, _ := ToJSON(resp)
+	w.Write([]byte(data + "\n"))
+}
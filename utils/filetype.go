@@ -0,0 +1,131 @@
+package utils
+
+import "strings"
+
+/*
+	文件类型识别
+*/
+
+// 按文件头识别出的类型
+const (
+	FileTypeUnknown = ""
+	FileTypeZip     = "zip"
+	FileTypeGzip    = "gzip"
+	FileTypeZstd    = "zstd"
+	FileTypeTar     = "tar"
+	FileTypeOle     = "ole" // doc/xls/ppt 等旧版office文档
+	FileTypePdf     = "pdf"
+	FileTypePng     = "png"
+	FileTypeJpeg    = "jpeg"
+	FileTypeGif     = "gif"
+	FileTypeWebp    = "webp"
+	FileTypeMp4     = "mp4"
+	FileTypeMov     = "mov" // QuickTime，ftyp的主品牌为 qt
+	FileTypeWebm    = "webm"
+	FileTypeText    = "text"
+)
+
+// SniffLen 识别文件类型需要读取的文件头长度
+const SniffLen = 512
+
+var magicNumbers = []struct {
+	offset int
+	magic  string
+	kind   string
+	prefix string // 文件开头还需匹配的内容，magic不在开头时用于排除误判
+}{
+	{0, "PK\x03\x04", FileTypeZip, ""},
+	{0, "PK\x05\x06", FileTypeZip, ""},
+	{0, "\x1f\x8b", FileTypeGzip, ""},
+	{0, "\x28\xb5\x2f\xfd", FileTypeZstd, ""},
+	{257, "ustar", FileTypeTar, ""},
+	{0, "\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1", FileTypeOle, ""},
+	{0, "%PDF-", FileTypePdf, ""},
+	{0, "\x89PNG\r\n\x1a\n", FileTypePng, ""},
+	{0, "\xff\xd8\xff", FileTypeJpeg, ""},
+	{0, "GIF87a", FileTypeGif, ""},
+	{0, "GIF89a", FileTypeGif, ""},
+	{8, "WEBP", FileTypeWebp, "RIFF"},
+	{4, "ftypqt  ", FileTypeMov, ""},
+	{4, "ftyp", FileTypeMp4, ""},
+	{0, "\x1a\x45\xdf\xa3", FileTypeWebm, ""},
+}
+
+// 扩展名对应的文件类型
+var extFileTypes = map[string]string{
+	".zip":     FileTypeZip,
+	".tar":     FileTypeTar,
+	".tar.gz":  FileTypeGzip,
+	".tgz":     FileTypeGzip,
+	".tar.zst": FileTypeZstd,
+	".docx":    FileTypeZip,
+	".xlsx":    FileTypeZip,
+	".pptx":    FileTypeZip,
+	".doc":     FileTypeOle,
+	".xls":     FileTypeOle,
+	".ppt":     FileTypeOle,
+	".pdf":     FileTypePdf,
+	".png":     FileTypePng,
+	".jpg":     FileTypeJpeg,
+	".jpeg":    FileTypeJpeg,
+	".gif":     FileTypeGif,
+	".webp":    FileTypeWebp,
+	".mp4":     FileTypeMp4,
+	".mov":     FileTypeMov,
+	".webm":    FileTypeWebm,
+	".txt":     FileTypeText,
+	".csv":     FileTypeText,
+	".json":    FileTypeText,
+	".html":    FileTypeText,
+}
+
+// DetectFileType 根据文件头的魔数识别文件类型
+func DetectFileType(head []byte) string {
+	for _, m := range magicNumbers {
+		if len(head) >= m.offset+len(m.magic) && string(head[m.offset:m.offset+len(m.magic)]) == m.magic && strings.HasPrefix(string(head), m.prefix) {
+			return m.kind
+		}
+	}
+	if len(head) > 0 && isText(head) {
+		return FileTypeText
+	}
+	return FileTypeUnknown
+}
+
+// isText 不含NUL和除空白外的控制字符即视为文本，不限定编码，GBK等编码导出的CSV同样是文本
+func isText(b []byte) bool {
+	for _, c := range b {
+		if c < 0x20 && c != '\t' && c != '\n' && c != '\v' && c != '\f' && c != '\r' || c == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckFileType 校验扩展名是否在允许列表中，以及文件内容是否与扩展名一致
+func CheckFileType(ext string, allowed []string, head []byte) error {
+	ext = strings.ToLower(ext)
+	found := false
+	for _, a := range allowed {
+		if strings.ToLower(a) == ext {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return NewError(ErrUnsupportedType, "不支持的文件类型: "+ext, nil)
+	}
+	want, ok := extFileTypes[ext]
+	if !ok {
+		// 没有登记魔数的扩展名只校验允许列表
+		return nil
+	}
+	// 文本内容可能恰好在某个偏移处出现二进制格式的魔数，扩展名为文本时先按文本判断
+	if want == FileTypeText && isText(head) {
+		return nil
+	}
+	if got := DetectFileType(head); got != want {
+		return NewError(ErrUnsupportedType, "文件内容与扩展名不符: "+ext, nil)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package utils
+
+import "testing"
+
+func TestDetectFileType(t *testing.T) {
+	tests := []struct {
+		name string
+		head string
+		want string
+	}{
+		{"utf8 csv", "名称,数量\r\n苹果,1\r\n", FileTypeText},
+		{"gbk csv", "\xc3\xfb\xb3\xc6,\xca\xfd\xc1\xbf\r\n", FileTypeText},
+		{"truncated utf8", "中文\xe4\xb8", FileTypeText},
+		{"tab separated", "a\tb\fc\n", FileTypeText},
+		{"nul byte", "abc\x00def", FileTypeUnknown},
+		{"control byte", "abc\x01def", FileTypeUnknown},
+		{"mp4", "\x00\x00\x00\x18ftypisom\x00\x00\x02\x00", FileTypeMp4},
+		{"mov", "\x00\x00\x00\x14ftypqt  \x00\x00\x02\x00", FileTypeMov},
+		{"zip", "PK\x03\x04rest", FileTypeZip},
+		{"webp", "RIFF\x24\x00\x00\x00WEBPVP8 ", FileTypeWebp},
+		{"webp without riff", "id,nameWEBP,1\n", FileTypeText},
+		{"empty", "", FileTypeUnknown},
+	}
+	for _, tt := range tests {
+		if got := DetectFileType([]byte(tt.head)); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckFileType(t *testing.T) {
+	mp4 := []byte("\x00\x00\x00\x18ftypisom\x00\x00\x02\x00")
+	mov := []byte("\x00\x00\x00\x14ftypqt  \x00\x00\x02\x00")
+	tests := []struct {
+		ext  string
+		head []byte
+		ok   bool
+	}{
+		{".csv", []byte("\xc3\xfb\xb3\xc6,1\r\n"), true},
+		{".CSV", nil, true},
+		{".mov", mov, true},
+		{".mov", mp4, false},
+		{".mp4", mov, false},
+		{".mp4", mp4, true},
+		{".exe", mp4, false},
+		// 文本中恰好出现魔数
+		{".txt", []byte("abc ftyp and more\n"), true},
+		{".csv", []byte("id,name,WEBP,1\r\n"), true},
+		{".csv", []byte("a,b\n" + string(make([]byte, 253)) + "ustar"), false},
+		{".webp", []byte("id,nameWEBP,1\n"), false},
+		{".txt", mp4, false},
+	}
+	allowed := []string{".csv", ".txt", ".mov", ".mp4", ".webp"}
+	for _, tt := range tests {
+		err := CheckFileType(tt.ext, allowed, tt.head)
+		if tt.ok && err != nil || !tt.ok && ErrorKind(err) != ErrUnsupportedType {
+			t.Errorf("CheckFileType(%q, %q) = %v", tt.ext, tt.head, err)
+		}
+	}
+}
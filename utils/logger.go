@@ -0,0 +1,325 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+	日志处理
+*/
+
+// LogConfig 日志配置
+type LogConfig struct {
+	Level          string        `yaml:"level"`           // 日志级别 debug/info/warn/error，默认info
+	Format         string        `yaml:"format"`          // 输出格式 text/json，默认text
+	Output         string        `yaml:"output"`          // 日志文件路径，为空时输出到标准错误
+	MaxSize        int           `yaml:"max_size"`        // 单个日志文件最大MB，0表示不按大小切割
+	RotateInterval time.Duration `yaml:"rotate_interval"` // 按时间切割的周期，0表示不按时间切割
+	MaxBackups     int           `yaml:"max_backups"`     // 保留的历史日志文件数，0表示全部保留
+	ReportCaller   bool          `yaml:"report_caller"`   // 是否记录调用位置
+}
+
+// Fields 日志字段
+type Fields = logrus.Fields
+
+type ctxKey int
+
+const (
+	requestIdKey ctxKey = iota
+	traceIdKey
+	userKey
+)
+
+var (
+	lgr        = logrus.New()
+	logWriter  *RotateWriter
+	loggerFile string
+)
+
+func init() {
+	_, loggerFile, _, _ = runtime.Caller(0)
+}
+
+// InitLogger 按配置初始化日志，可重复调用
+func InitLogger(cfg LogConfig) error {
+	level := logrus.InfoLevel
+	if cfg.Level != "" {
+		var err error
+		if level, err = logrus.ParseLevel(cfg.Level); err != nil {
+			return err
+		}
+	}
+	var formatter logrus.Formatter
+	switch strings.ToLower(cfg.Format) {
+	case "", "text":
+		formatter = &logrus.TextFormatter{FullTimestamp: true, TimestampFormat: time.RFC3339}
+	case "json":
+		formatter = &logrus.JSONFormatter{TimestampFormat: time.RFC3339}
+	default:
+		return fmt.Errorf("unknown log format %q", cfg.Format)
+	}
+	var out io.Writer = os.Stderr
+	prev := logWriter
+	if cfg.Output != "" {
+		// 已输出到文件时在原writer内切换文件，进行中的写入不会写到已关闭的文件
+		if prev != nil {
+			if err := prev.Reset(cfg.Output, cfg.MaxSize, cfg.RotateInterval, cfg.MaxBackups); err != nil {
+				return err
+			}
+		} else {
+			w, err := NewRotateWriter(cfg.Output, cfg.MaxSize, cfg.RotateInterval, cfg.MaxBackups)
+			if err != nil {
+				return err
+			}
+			logWriter = w
+		}
+		out = logWriter
+	}
+
+	lgr.SetLevel(level)
+	lgr.SetFormatter(formatter)
+	lgr.SetOutput(out)
+	lgr.ReplaceHooks(make(logrus.LevelHooks))
+	if cfg.ReportCaller {
+		lgr.AddHook(callerHook{})
+	}
+	// 改为输出到标准错误，SetOutput之后不会再有写入原文件
+	if cfg.Output == "" && prev != nil {
+		prev.Close()
+		logWriter = nil
+	}
+	return nil
+}
+
+// Logger 返回全局日志实例
+func Logger() *logrus.Logger {
+	return lgr
+}
+
+// WithFields 带字段记录日志
+func WithFields(fields Fields) *logrus.Entry {
+	return lgr.WithFields(fields)
+}
+
+// WithContext 从上下文中取出请求ID和链路ID作为日志字段
+func WithContext(ctx context.Context) *logrus.Entry {
+	fields := Fields{}
+	if id := RequestIdFromContext(ctx); id != "" {
+		fields["request_id"] = id
+	}
+	if id := TraceIdFromContext(ctx); id != "" {
+		fields["trace_id"] = id
+	}
+	if user := UserFromContext(ctx); user != "" {
+		fields["user"] = user
+	}
+	return lgr.WithContext(ctx).WithFields(fields)
+}
+
+// ContextWithRequestId 在上下文中保存请求ID
+func ContextWithRequestId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIdKey, id)
+}
+
+// RequestIdFromContext 获取上下文中的请求ID
+func RequestIdFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIdKey).(string)
+	return id
+}
+
+// ContextWithTraceId 在上下文中保存链路ID
+func ContextWithTraceId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIdKey, id)
+}
+
+// TraceIdFromContext 获取上下文中的链路ID
+func TraceIdFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(traceIdKey).(string)
+	return id
+}
+
+// ContextWithUser 在上下文中保存当前操作人
+func ContextWithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey, user)
+}
+
+// UserFromContext 获取上下文中的操作人，未传入时为空
+func UserFromContext(ctx context.Context) string {
+	user, _ := ctx.Value(userKey).(string)
+	return user
+}
+
+func Debugf(f string, args ...interface{}) {
+	lgr.Debugf(f, args...)
+}
+func Infof(f string, args ...interface{}) {
+	lgr.Infof(f, args...)
+}
+func Warnf(f string, args ...interface{}) {
+	lgr.Warnf(f, args...)
+}
+func Errorf(f string, args ...interface{}) {
+	lgr.Errorf(f, args...)
+}
+func Fatalf(f string, args ...interface{}) {
+	lgr.Fatalf(f, args...)
+}
+
+// callerHook 记录业务代码的调用位置，跳过logrus及本文件的封装函数
+type callerHook struct{}
+
+func (callerHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (callerHook) Fire(e *logrus.Entry) error {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.Contains(frame.Function, "github.com/sirupsen/logrus") && frame.File != loggerFile {
+			e.Data["caller"] = fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)
+			return nil
+		}
+		if !more {
+			return nil
+		}
+	}
+}
+
+// RotateWriter 按大小和时间切割的日志文件
+type RotateWriter struct {
+	sync.Mutex
+	filename   string
+	maxSize    int64
+	interval   time.Duration
+	maxBackups int
+	file       *os.File
+	size       int64
+	period     time.Time
+}
+
+// NewRotateWriter 创建日志文件，maxSize单位为MB
+func NewRotateWriter(filename string, maxSize int, interval time.Duration, maxBackups int) (*RotateWriter, error) {
+	w := &RotateWriter{
+		filename:   filename,
+		maxSize:    int64(maxSize) * 1024 * 1024,
+		interval:   interval,
+		maxBackups: maxBackups,
+	}
+	if err := IsFolder(filepath.Dir(filename)); err != nil {
+		return nil, err
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *RotateWriter) open() error {
+	f, err := os.OpenFile(w.filename, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.file = f
+	w.size = info.Size()
+	w.period = w.currentPeriod()
+	return nil
+}
+
+func (w *RotateWriter) currentPeriod() time.Time {
+	if w.interval <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Truncate(w.interval)
+}
+
+// Reset 切换到新的日志文件和切割配置，在锁内替换文件后关闭原文件
+func (w *RotateWriter) Reset(filename string, maxSize int, interval time.Duration, maxBackups int) error {
+	next, err := NewRotateWriter(filename, maxSize, interval, maxBackups)
+	if err != nil {
+		return err
+	}
+	w.Lock()
+	defer w.Unlock()
+	old := w.file
+	w.filename, w.maxSize, w.interval, w.maxBackups = next.filename, next.maxSize, next.interval, next.maxBackups
+	w.file, w.size, w.period = next.file, next.size, next.period
+	return old.Close()
+}
+
+func (w *RotateWriter) Write(p []byte) (int, error) {
+	w.Lock()
+	defer w.Unlock()
+	if (w.maxSize > 0 && w.size+int64(len(p)) > w.maxSize && w.size > 0) ||
+		(w.interval > 0 && !w.currentPeriod().Equal(w.period)) {
+		if err := w.rotate(); err != nil {
+			// 切割失败时继续写入原文件，下次写入再重试
+			fmt.Fprintf(os.Stderr, "rotate log file err: %v\n", err)
+			if w.file == nil {
+				return 0, err
+			}
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// rotate 将当前文件重命名为带时间后缀的备份并打开新文件，关闭或重命名失败时重新打开原文件
+func (w *RotateWriter) rotate() error {
+	err := w.file.Close()
+	if err == nil {
+		backup := w.filename + "." + time.Now().Format("20060102-150405.000")
+		err = os.Rename(w.filename, backup)
+	}
+	w.file = nil
+	if oerr := w.open(); oerr != nil {
+		if err == nil {
+			err = oerr
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if w.maxBackups > 0 {
+		backups, err := filepath.Glob(w.filename + ".*")
+		if err != nil {
+			return err
+		}
+		sort.Strings(backups)
+		for len(backups) > w.maxBackups {
+			os.Remove(backups[0])
+			backups = backups[1:]
+		}
+	}
+	return nil
+}
+
+// Close 关闭日志文件
+func (w *RotateWriter) Close() error {
+	w.Lock()
+	defer w.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Close()
+}
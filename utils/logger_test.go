@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestRotateWriter(t *testing.T, maxBackups int) (*RotateWriter, string) {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "logs", "app.log")
+	w, err := NewRotateWriter(name, 0, 0, maxBackups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { w.Close() })
+	return w, name
+}
+
+func backups(t *testing.T, name string) []string {
+	t.Helper()
+	list, err := filepath.Glob(name + ".*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func writeLog(t *testing.T, w *RotateWriter, s string) {
+	t.Helper()
+	// 备份文件名精确到毫秒
+	time.Sleep(2 * time.Millisecond)
+	if n, err := w.Write([]byte(s)); err != nil || n != len(s) {
+		t.Fatalf("write = %d, %v", n, err)
+	}
+}
+
+func readFile(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRotateBySize(t *testing.T) {
+	w, name := newTestRotateWriter(t, 0)
+	w.maxSize = 10
+	writeLog(t, w, "12345\n")
+	writeLog(t, w, "abc\n")
+	writeLog(t, w, "next\n")
+	list := backups(t, name)
+	if len(list) != 1 || readFile(t, list[0]) != "12345\nabc\n" {
+		t.Fatalf("backups = %v", list)
+	}
+	if got := readFile(t, name); got != "next\n" {
+		t.Fatalf("current = %q", got)
+	}
+	// 单条超过上限时写入空文件，不会无限切割
+	writeLog(t, w, strings.Repeat("x", 20))
+	if got := readFile(t, name); got != strings.Repeat("x", 20) || len(backups(t, name)) != 2 {
+		t.Fatalf("current = %q", got)
+	}
+}
+
+func TestRotateByTime(t *testing.T) {
+	w, name := newTestRotateWriter(t, 0)
+	w.interval = time.Hour
+	w.period = w.currentPeriod()
+	writeLog(t, w, "a\n")
+	if len(backups(t, name)) != 0 {
+		t.Fatal("rotated within period")
+	}
+	w.period = w.period.Add(-time.Hour)
+	writeLog(t, w, "b\n")
+	list := backups(t, name)
+	if len(list) != 1 || readFile(t, list[0]) != "a\n" || readFile(t, name) != "b\n" {
+		t.Fatalf("backups = %v", list)
+	}
+}
+
+func TestRotatePrune(t *testing.T) {
+	w, name := newTestRotateWriter(t, 2)
+	w.maxSize = 1
+	for _, s := range []string{"1", "2", "3", "4", "5"} {
+		writeLog(t, w, s)
+	}
+	list := backups(t, name)
+	if len(list) != 2 || readFile(t, list[0]) != "3" || readFile(t, list[1]) != "4" {
+		t.Fatalf("backups = %v", list)
+	}
+}
+
+func TestRotateFailed(t *testing.T) {
+	w, name := newTestRotateWriter(t, 0)
+	w.maxSize = 1
+	writeLog(t, w, "a")
+	// 文件被外部删除时重命名失败，重新打开文件继续写入
+	if err := os.Remove(name); err != nil {
+		t.Fatal(err)
+	}
+	writeLog(t, w, "b")
+	writeLog(t, w, "c")
+	list := backups(t, name)
+	if len(list) != 1 || readFile(t, list[0]) != "b" || readFile(t, name) != "c" {
+		t.Fatalf("backups = %v", list)
+	}
+}
+
+func TestInitLoggerSwitchFile(t *testing.T) {
+	defer InitLogger(LogConfig{})
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "a.log"), filepath.Join(dir, "b.log")
+	if err := InitLogger(LogConfig{Output: first}); err != nil {
+		t.Fatal(err)
+	}
+	w := logWriter
+	Logger().Info("to first")
+	if err := InitLogger(LogConfig{Output: second}); err != nil {
+		t.Fatal(err)
+	}
+	// 切换文件时复用同一个writer
+	if logWriter != w || Logger().Out != w {
+		t.Fatal("writer replaced")
+	}
+	Logger().Info("to second")
+	if a, b := readFile(t, first), readFile(t, second); !strings.Contains(a, "to first") || strings.Contains(a, "to second") || !strings.Contains(b, "to second") {
+		t.Fatalf("first = %q, second = %q", a, b)
+	}
+	if err := InitLogger(LogConfig{}); err != nil || logWriter != nil || Logger().Out != os.Stderr {
+		t.Fatalf("switch to stderr: %v", err)
+	}
+}
@@ -0,0 +1,27 @@
+package utils
+
+import "time"
+
+// Metrics 缓存、锁和限流器的埋点，由 metrics 包在启用时设置，未启用时埋点只有一次判空
+type Metrics interface {
+	CacheGet(cache, result string)
+	LockAcquire(lock, result string, wait time.Duration)
+	LimiterReject(method string)
+}
+
+// 缓存查询和加锁的结果
+const (
+	ResultHit      = "hit"
+	ResultMiss     = "miss"
+	ResultAcquired = "acquired"
+	ResultBusy     = "busy"
+	ResultError    = "error"
+)
+
+// metrics 为nil时不记录
+var metrics Metrics
+
+// SetMetrics 设置埋点，需在启动时调用，之后不再修改
+func SetMetrics(m Metrics) {
+	metrics = m
+}
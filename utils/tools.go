@@ -1,17 +1,14 @@
 package utils
 
 import (
-	"archive/zip"
 	"bytes"
 	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"encoding/json"
-	"fmt"
 	"io"
 	"math"
 	"math/rand"
-	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -20,7 +17,6 @@ import (
 	"time"
 
 	"github.com/go-redis/redis/v8"
-	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	clientv3 "go.etcd.io/etcd/client/v3"
 
@@ -227,124 +223,6 @@ func IsFolder(name string) (err error) {
 	return
 }
 
-// 文件目录压缩
-func AddDirToZip(zipFileName string, dir string, pathInZip string) error {
-	// 创建zip文件
-	zipFile, err := os.Create(zipFileName)
-	if err != nil {
-		return err
-	}
-	defer zipFile.Close()
-	writer := zip.NewWriter(zipFile)
-	defer writer.Close()
-	// 获取目录信息
-	dirInfo, err := os.Stat(dir)
-	if err != nil {
-		return err
-	}
-	// 判断是否为目录
-	if dirInfo.IsDir() {
-		// 读取目录下的文件列表
-		files, err := os.ReadDir(dir)
-		if err != nil {
-			return err
-		}
-		// 遍历文件列表
-		for _, file := range files {
-			// 构造在zip文件中的路径
-			newPathInZip := filepath.Join(pathInZip, file.Name())
-			if file.IsDir() {
-				// 如果是目录，递归调用addDirToZip函数
-				if err = AddDirToZip(zipFileName, filepath.Join(dir, file.Name()), newPathInZip); err != nil {
-					return err
-				}
-			} else {
-				// 如果是文件，打开文件并写入zip文件
-				fileToZip, err := os.Open(filepath.Join(dir, file.Name()))
-				if err != nil {
-					return err
-				}
-				defer fileToZip.Close()
-				zipFile, err := writer.Create(newPathInZip)
-				if err != nil {
-					return err
-				}
-				_, err = io.Copy(zipFile, fileToZip)
-				if err != nil {
-					return err
-				}
-			}
-		}
-	} else {
-		fileToZip, err := os.Open(dir)
-		if err != nil {
-			return err
-		}
-		defer fileToZip.Close()
-		zipFile, err := writer.Create(pathInZip)
-		if err != nil {
-			return err
-		}
-		_, err = io.Copy(zipFile, fileToZip)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-// zip文件解压
-func Unzip2(src string, dest string) (string, error) {
-	r, err := zip.OpenReader(src)
-	if err != nil {
-		return "", err
-	}
-	var filenames = r.File[0].Name
-	defer r.Close()
-	for _, f := range r.File {
-
-		// Store filename/path for returning and using later on
-		fpath := filepath.Join(dest, f.Name)
-
-		// Check for ZipSlip. More Info: http://bit.ly/2MsjAWE
-		if !strings.HasPrefix(fpath, filepath.Clean(dest)+string(os.PathSeparator)) {
-			return filenames, fmt.Errorf("%s: illegal file path", fpath)
-		}
-
-		if f.FileInfo().IsDir() {
-			// Make Folder
-			os.MkdirAll(fpath, os.ModePerm)
-			continue
-		}
-
-		// Make File
-		if err = os.MkdirAll(filepath.Dir(fpath), os.ModePerm); err != nil {
-			return filenames, err
-		}
-
-		outFile, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
-		if err != nil {
-			return filenames, err
-		}
-
-		rc, err := f.Open()
-		if err != nil {
-			return filenames, err
-		}
-
-		_, err = io.Copy(outFile, rc)
-
-		// Close the file without defer to close before next iteration of loop
-		outFile.Close()
-		rc.Close()
-
-		if err != nil {
-			return filenames, err
-		}
-	}
-	return filenames, nil
-}
-
 /*
 	加密解密
 */
@@ -384,7 +262,7 @@ func RandomString(length int) string {
 
 // HTTPGet 发送HTTP GET请求
 func HTTPGet(url string) ([]byte, error) {
-	resp, err := http.Get(url)
+	resp, err := httpClient.Get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -394,7 +272,7 @@ func HTTPGet(url string) ([]byte, error) {
 
 // HTTPPost 发送HTTP POST请求
 func HTTPPost(url string, body []byte) ([]byte, error) {
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(body))
+	resp, err := httpClient.Post(url, "application/json", bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
@@ -402,28 +280,6 @@ func HTTPPost(url string, body []byte) ([]byte, error) {
 	return io.ReadAll(resp.Body)
 }
 
-/*
-	日志处理
-*/
-
-var lgr = logrus.New()
-
-func Debugf(f string, args ...interface{}) {
-	lgr.Debugf(f, args...)
-}
-func Infof(f string, args ...interface{}) {
-	lgr.Infof(f, args...)
-}
-func Warnf(f string, args ...interface{}) {
-	lgr.Warnf(f, args...)
-}
-func Errorf(f string, args ...interface{}) {
-	lgr.Errorf(f, args...)
-}
-func Fatalf(f string, args ...interface{}) {
-	lgr.Fatalf(f, args...)
-}
-
 /*
 	分布式任务处理
 */
@@ -454,12 +310,20 @@ func NewLimiter(r rate.Limit, b int) *Limiter {
 
 // Allow 检查是否允许执行
 func (l *Limiter) Allow() bool {
-	return l.limiter.Allow()
+	ok := l.limiter.Allow()
+	if !ok && metrics != nil {
+		metrics.LimiterReject("allow")
+	}
+	return ok
 }
 
 // Wait 等待直到允许执行
 func (l *Limiter) Wait(ctx context.Context) error {
-	return l.limiter.Wait(ctx)
+	err := l.limiter.Wait(ctx)
+	if err != nil && metrics != nil {
+		metrics.LimiterReject("wait")
+	}
+	return err
 }
 
 // RedisLock 基于Redis的分布式锁
@@ -476,7 +340,12 @@ func NewRedisLock(client *redis.Client, key, value string, ttl time.Duration) *R
 
 // Lock 获取锁
 func (l *RedisLock) Lock(ctx context.Context) (bool, error) {
-	return l.client.SetNX(ctx, l.key, l.value, l.ttl).Result()
+	start := time.Now()
+	ok, err := l.client.SetNX(ctx, l.key, l.value, l.ttl).Result()
+	if metrics != nil {
+		metrics.LockAcquire("redis", lockResult(ok, err), time.Since(start))
+	}
+	return ok, err
 }
 
 // Unlock 释放锁
@@ -506,10 +375,26 @@ func NewEtcdLock(client *clientv3.Client, key string, ttl time.Duration) *EtcdLo
 func (l *EtcdLock) Lock(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, l.ttl)
 	defer cancel()
+	start := time.Now()
 	_, err := l.client.Put(ctx, l.key, "locked", clientv3.WithLease(clientv3.LeaseID(l.ttl)))
+	if metrics != nil {
+		metrics.LockAcquire("etcd", lockResult(err == nil, err), time.Since(start))
+	}
 	return err
 }
 
+// lockResult 加锁结果的埋点标签
+func lockResult(ok bool, err error) string {
+	switch {
+	case err != nil:
+		return ResultError
+	case ok:
+		return ResultAcquired
+	default:
+		return ResultBusy
+	}
+}
+
 // Unlock 释放锁
 func (l *EtcdLock) Unlock(ctx context.Context) error {
 	_, err := l.client.Delete(ctx, l.key)
@@ -545,17 +430,32 @@ func (c *Cache) Set(key string, value interface{}, duration time.Duration) {
 // Get 获取缓存项
 func (c *Cache) Get(key string) (interface{}, bool) {
 	c.RLock()
-	defer c.RUnlock()
 	item, found := c.items[key]
-	if !found {
-		return nil, false
+	c.RUnlock()
+	if found && time.Now().UnixNano() > item.expiration {
+		found = false
+	}
+	if metrics != nil {
+		metrics.CacheGet("memory", cacheResult(found, nil))
 	}
-	if time.Now().UnixNano() > item.expiration {
+	if !found {
 		return nil, false
 	}
 	return item.value, true
 }
 
+// cacheResult 缓存查询结果的埋点标签
+func cacheResult(found bool, err error) string {
+	switch {
+	case err != nil:
+		return ResultError
+	case found:
+		return ResultHit
+	default:
+		return ResultMiss
+	}
+}
+
 // Delete 删除缓存项
 func (c *Cache) Delete(key string) {
 	c.Lock()
@@ -576,17 +476,57 @@ func NewRedisCache(addr string) *RedisCache {
 	return &RedisCache{client: client}
 }
 
+// NewRedisCacheWithClient 使用已有的客户端创建RedisCache
+func NewRedisCacheWithClient(client *redis.Client) *RedisCache {
+	return &RedisCache{client: client}
+}
+
 // Set 设置缓存项
 func (c *RedisCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
-	return c.client.Set(ctx, key, value, expiration).Err()
+	ctx, end := redisSpan(ctx, "set")
+	err := c.client.Set(ctx, key, value, expiration).Err()
+	end(err)
+	return err
 }
 
 // Get 获取缓存项
 func (c *RedisCache) Get(ctx context.Context, key string) (string, error) {
-	return c.client.Get(ctx, key).Result()
+	ctx, end := redisSpan(ctx, "get")
+	v, err := c.client.Get(ctx, key).Result()
+	// 键不存在是未命中，不算查询失败
+	found, qerr := true, err
+	if err == redis.Nil {
+		found, qerr = false, nil
+	}
+	end(qerr)
+	if metrics != nil {
+		metrics.CacheGet("redis", cacheResult(found, qerr))
+	}
+	return v, err
 }
 
 // Delete 删除缓存项
 func (c *RedisCache) Delete(ctx context.Context, key string) error {
-	return c.client.Del(ctx, key).Err()
+	ctx, end := redisSpan(ctx, "del")
+	err := c.client.Del(ctx, key).Err()
+	end(err)
+	return err
+}
+
+// Close 关闭客户端
+func (c *RedisCache) Close() error {
+	return c.client.Close()
+}
+
+// Ping 检查Redis连接是否可用
+func (c *RedisCache) Ping(ctx context.Context) error {
+	ctx, end := redisSpan(ctx, "ping")
+	err := c.client.Ping(ctx).Err()
+	end(err)
+	return err
+}
+
+// redisSpan Redis命令的子span
+func redisSpan(ctx context.Context, op string) (context.Context, func(error)) {
+	return StartSpan(ctx, "redis "+op, "db.system", "redis", "db.operation", op)
 }
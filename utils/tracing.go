@@ -0,0 +1,26 @@
+package utils
+
+import "context"
+
+// Tracer 链路追踪的埋点，由 trace 包在启用时设置，attrs为键值对
+type Tracer interface {
+	Start(ctx context.Context, name string, attrs ...string) (context.Context, func(err error))
+}
+
+// tracer 为nil时不创建span
+var tracer Tracer
+
+// SetTracer 设置链路追踪，需在启动时调用，之后不再修改
+func SetTracer(t Tracer) {
+	tracer = t
+}
+
+func noopEnd(error) {}
+
+// StartSpan 开始一个子span，返回的函数结束span并记录错误，未启用追踪时返回原上下文
+func StartSpan(ctx context.Context, name string, attrs ...string) (context.Context, func(err error)) {
+	if tracer == nil {
+		return ctx, noopEnd
+	}
+	return tracer.Start(ctx, name, attrs...)
+}
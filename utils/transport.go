@@ -0,0 +1,179 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+/*
+	可替换的HTTP传输层
+*/
+
+// httpClient HTTPGet/HTTPPost 使用的客户端，传输层可通过 SetTransport 替换
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// SetTransport 替换HTTP客户端的传输层，传入nil时恢复默认传输层
+func SetTransport(rt http.RoundTripper) {
+	httpClient.Transport = rt
+}
+
+// HTTPClient 返回 HTTPGet/HTTPPost 共用的客户端
+func HTTPClient() *http.Client {
+	return httpClient
+}
+
+// RoundTripperFunc 将普通函数适配为 http.RoundTripper，便于测试中模拟响应
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// ErrFixtureNotFound 回放时找不到对应请求的录制文件
+var ErrFixtureNotFound = errors.New("fixture not found")
+
+// Fixture 录制的一次请求/响应，请求体和响应体按原始字节以base64保存，非UTF-8内容不会损坏
+type Fixture struct {
+	Request struct {
+		Method string      `json:"method"`
+		URL    string      `json:"url"`
+		Header http.Header `json:"header,omitempty"`
+		Body   []byte      `json:"body,omitempty"`
+	} `json:"request"`
+	Response struct {
+		StatusCode int         `json:"status_code"`
+		Header     http.Header `json:"header,omitempty"`
+		Body       []byte      `json:"body,omitempty"`
+	} `json:"response"`
+}
+
+// fixtureName 按请求方法、URL和请求体生成录制文件名
+func fixtureName(method, url string, body []byte) string {
+	return Md5(method+" "+url+"\n"+string(body)) + ".json"
+}
+
+// readRequestBody 读取并还原请求体
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// Recorder 录制器，转发请求并把请求/响应保存到录制目录
+type Recorder struct {
+	mu   sync.Mutex
+	dir  string
+	next http.RoundTripper
+}
+
+// NewRecorder 创建录制器，next为nil时使用 http.DefaultTransport
+func NewRecorder(dir string, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{dir: dir, next: next}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	var f Fixture
+	f.Request.Method = req.Method
+	f.Request.URL = req.URL.String()
+	f.Request.Header = req.Header.Clone()
+	// 凭证不写入录制文件
+	f.Request.Header.Del("Authorization")
+	f.Request.Header.Del("Cookie")
+	f.Request.Body = reqBody
+	f.Response.StatusCode = resp.StatusCode
+	// 调用方仍能拿到Set-Cookie，只是不写入录制文件
+	f.Response.Header = resp.Header.Clone()
+	f.Response.Header.Del("Set-Cookie")
+	f.Response.Body = respBody
+	data, err := json.MarshalIndent(&f, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err = IsFolder(r.dir); err != nil {
+		return nil, err
+	}
+	name := filepath.Join(r.dir, fixtureName(req.Method, f.Request.URL, reqBody))
+	if err = os.WriteFile(name, data, 0644); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Replayer 回放器，从录制目录读取响应，不访问网络
+type Replayer struct {
+	dir string
+}
+
+// NewReplayer 创建回放器
+func NewReplayer(dir string) *Replayer {
+	return &Replayer{dir: dir}
+}
+
+func (p *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	url := req.URL.String()
+	data, err := os.ReadFile(filepath.Join(p.dir, fixtureName(req.Method, url, reqBody)))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s %s", ErrFixtureNotFound, req.Method, url)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f Fixture
+	if err = json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	if f.Response.Header == nil {
+		f.Response.Header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Response.StatusCode, http.StatusText(f.Response.StatusCode)),
+		StatusCode:    f.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        f.Response.Header,
+		Body:          io.NopCloser(bytes.NewReader(f.Response.Body)),
+		ContentLength: int64(len(f.Response.Body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	// GBK编码的"你好"和不合法的UTF-8字节
+	respBody := []byte{0xc4, 0xe3, 0xba, 0xc3, 0xff, 0x00, 0x80}
+	reqBody := []byte{0xfe, 0xed, 0xfa, 0xce}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !bytes.Equal(body, reqBody) {
+			t.Errorf("server got body %x", body)
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=gbk")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.WriteHeader(http.StatusCreated)
+		w.Write(respBody)
+	}))
+	url := srv.URL + "/echo?a=1"
+	dir := t.TempDir()
+
+	do := func(rt http.RoundTripper) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, url, bytes.NewReader(reqBody))
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := (&http.Client{Transport: rt}).Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	resp := do(NewRecorder(dir, nil))
+	recorded, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !bytes.Equal(recorded, respBody) {
+		t.Fatalf("recorder changed body: %x", recorded)
+	}
+	if resp.Header.Get("Set-Cookie") != "session=secret" {
+		t.Fatal("recorder removed Set-Cookie from live response")
+	}
+	srv.Close()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("fixtures = %v", files)
+	}
+	data, _ := os.ReadFile(files[0])
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		t.Fatal(err)
+	}
+	if f.Request.Header.Get("Authorization") != "" || f.Response.Header.Get("Set-Cookie") != "" {
+		t.Fatal("credentials written to fixture")
+	}
+
+	resp = do(NewReplayer(dir))
+	replayed, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated || resp.Header.Get("Content-Type") != "text/plain; charset=gbk" {
+		t.Fatalf("replayed %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if !bytes.Equal(replayed, respBody) {
+		t.Fatalf("replayed body %x, want %x", replayed, respBody)
+	}
+}
+
+func TestReplayNotFound(t *testing.T) {
+	client := &http.Client{Transport: NewReplayer(t.TempDir())}
+	_, err := client.Get("http://example.com/missing")
+	if !errors.Is(err, ErrFixtureNotFound) {
+		t.Fatalf("err = %v", err)
+	}
+}
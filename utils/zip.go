@@ -0,0 +1,519 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+/*
+	压缩包解压
+*/
+
+// ErrEmptyArchive 压缩文件中没有任何条目
+var ErrEmptyArchive = errors.New("empty archive")
+
+// ExtractLimits 解压限制，字段为0表示不限制
+type ExtractLimits struct {
+	MaxTotalSize int64   // 解压后总字节数
+	MaxFileSize  int64   // 单个文件解压后字节数
+	MaxEntries   int     // 条目数
+	MaxDepth     int     // 目录层级
+	MaxRatio     float64 // 解压后与压缩前的大小之比
+}
+
+// DefaultExtractLimits 默认解压限制
+var DefaultExtractLimits = ExtractLimits{
+	MaxTotalSize: 1 << 30,
+	MaxFileSize:  512 << 20,
+	MaxEntries:   10000,
+	MaxDepth:     16,
+	MaxRatio:     100,
+}
+
+// ratioMinSize 解压后不超过该大小时不检查压缩比，避免误判内容重复的小文件
+const ratioMinSize = 1 << 20
+
+// ExtractGuard 解压过程中的安全检查，按实际写出的字节数计数，不信任压缩包头中声明的大小
+type ExtractGuard struct {
+	dest    string
+	limits  ExtractLimits
+	entries int
+	total   int64
+}
+
+// NewExtractGuard 创建解压检查，dest为解压目标目录
+func NewExtractGuard(dest string, limits ExtractLimits) *ExtractGuard {
+	return &ExtractGuard{dest: filepath.Clean(dest), limits: limits}
+}
+
+// Entry 检查条目数量、路径和类型，返回条目在目标目录中的路径
+func (g *ExtractGuard) Entry(name string, mode fs.FileMode) (string, error) {
+	g.entries++
+	if g.limits.MaxEntries > 0 && g.entries > g.limits.MaxEntries {
+		return "", NewError(ErrTooLarge, "压缩文件包含的文件过多", fmt.Errorf("more than %d entries", g.limits.MaxEntries))
+	}
+	if mode&fs.ModeSymlink != 0 {
+		return "", NewError(ErrInvalidParam, "压缩文件包含符号链接", fmt.Errorf("%s: symlink", name))
+	}
+	if !mode.IsDir() && !mode.IsRegular() {
+		return "", NewError(ErrInvalidParam, "压缩文件包含特殊文件", fmt.Errorf("%s: mode %s", name, mode))
+	}
+	fpath := filepath.Join(g.dest, name)
+	// ./ 等指向目标目录本身的目录条目，由 tar -C dir . 等方式打包时产生
+	if fpath == g.dest && mode.IsDir() {
+		return fpath, nil
+	}
+	// Check for ZipSlip. More Info: http://bit.ly/2MsjAWE
+	if !strings.HasPrefix(fpath, g.dest+string(os.PathSeparator)) {
+		return "", NewError(ErrInvalidParam, "压缩文件包含非法路径", fmt.Errorf("%s: illegal file path", name))
+	}
+	if g.limits.MaxDepth > 0 {
+		rel, _ := filepath.Rel(g.dest, fpath)
+		if depth := len(strings.Split(rel, string(os.PathSeparator))); depth > g.limits.MaxDepth {
+			return "", NewError(ErrTooLarge, "压缩文件目录层级过深", fmt.Errorf("%s: depth %d", name, depth))
+		}
+	}
+	return fpath, nil
+}
+
+// storageWriter 写入失败时返回 ErrStorage，与读取来源时的格式错误区分
+type storageWriter struct {
+	w io.Writer
+}
+
+// StorageWriter 包装写入磁盘等存储的w，写入失败时返回 ErrStorage
+func StorageWriter(w io.Writer) io.Writer {
+	return storageWriter{w}
+}
+
+func (s storageWriter) Write(p []byte) (int, error) {
+	n, err := s.w.Write(p)
+	if err != nil {
+		err = NewError(ErrStorage, "解压文件失败", err)
+	}
+	return n, err
+}
+
+// Copy 复制条目内容并检查大小，compressed为条目压缩后的大小，未知时传0。
+// 写入dst失败时返回 ErrStorage，其余非 *Error 的错误来自读取压缩包
+func (g *ExtractGuard) Copy(dst io.Writer, src io.Reader, compressed int64) (int64, error) {
+	dst = storageWriter{dst}
+	limit := int64(-1)
+	if g.limits.MaxFileSize > 0 {
+		limit = g.limits.MaxFileSize
+	}
+	if g.limits.MaxTotalSize > 0 && (limit < 0 || g.limits.MaxTotalSize-g.total < limit) {
+		limit = g.limits.MaxTotalSize - g.total
+	}
+	if g.limits.MaxRatio > 0 && compressed > 0 {
+		ratioLimit := int64(float64(compressed) * g.limits.MaxRatio)
+		if ratioLimit < ratioMinSize {
+			ratioLimit = ratioMinSize
+		}
+		if limit < 0 || ratioLimit < limit {
+			limit = ratioLimit
+		}
+	}
+	if limit >= 0 {
+		src = io.LimitReader(src, limit+1)
+	}
+	n, err := io.Copy(dst, src)
+	g.total += n
+	if err != nil {
+		return n, err
+	}
+	if limit >= 0 && n > limit {
+		return n, g.overflow(n, compressed)
+	}
+	return n, nil
+}
+
+// CheckRatio 检查整体压缩比，用于无法取得单个条目压缩大小的格式
+func (g *ExtractGuard) CheckRatio(compressed int64) error {
+	if g.limits.MaxRatio > 0 && compressed > 0 && g.total > ratioMinSize && float64(g.total)/float64(compressed) > g.limits.MaxRatio {
+		return NewError(ErrTooLarge, "压缩比异常", fmt.Errorf("ratio %.1f", float64(g.total)/float64(compressed)))
+	}
+	return nil
+}
+
+// Total 已解压的总字节数
+func (g *ExtractGuard) Total() int64 {
+	return g.total
+}
+
+func (g *ExtractGuard) overflow(n, compressed int64) error {
+	switch {
+	case g.limits.MaxTotalSize > 0 && g.total > g.limits.MaxTotalSize:
+		return NewError(ErrTooLarge, "压缩文件解压后过大", fmt.Errorf("more than %d bytes", g.limits.MaxTotalSize))
+	case g.limits.MaxFileSize > 0 && n > g.limits.MaxFileSize:
+		return NewError(ErrTooLarge, "压缩文件中的单个文件过大", fmt.Errorf("more than %d bytes", g.limits.MaxFileSize))
+	default:
+		return NewError(ErrTooLarge, "压缩比异常", fmt.Errorf("%d bytes from %d compressed", n, compressed))
+	}
+}
+
+// zip文件解压
+func Unzip2(src string, dest string) (string, error) {
+	return UnzipWithLimits(src, dest, DefaultExtractLimits)
+}
+
+// UnzipWithLimits 按限制解压zip文件，返回压缩包中第一个条目的名称
+func UnzipWithLimits(src string, dest string, limits ExtractLimits) (string, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return "", NewError(ErrStorage, "读取压缩文件失败", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", NewError(ErrStorage, "读取压缩文件失败", err)
+	}
+	return UnzipReader(f, info.Size(), dest, limits)
+}
+
+// UnzipReader 从 io.ReaderAt 按限制解压zip，返回压缩包中第一个条目的名称
+func UnzipReader(ra io.ReaderAt, size int64, dest string, limits ExtractLimits) (string, error) {
+	r, err := zip.NewReader(ra, size)
+	if err != nil {
+		return "", NewError(ErrInvalidParam, "压缩文件无法解析", err)
+	}
+	if len(r.File) == 0 {
+		return "", NewError(ErrInvalidParam, "压缩文件为空", ErrEmptyArchive)
+	}
+	// 先按目录中声明的数据拒绝明显超限的压缩包，实际大小在解压时再次检查
+	if limits.MaxEntries > 0 && len(r.File) > limits.MaxEntries {
+		return "", NewError(ErrTooLarge, "压缩文件包含的文件过多", fmt.Errorf("%d entries", len(r.File)))
+	}
+	var declared uint64
+	for _, f := range r.File {
+		declared += f.UncompressedSize64
+	}
+	if limits.MaxTotalSize > 0 && declared > uint64(limits.MaxTotalSize) {
+		return "", NewError(ErrTooLarge, "压缩文件解压后过大", fmt.Errorf("%d bytes declared", declared))
+	}
+
+	var filenames = r.File[0].Name
+	guard := NewExtractGuard(dest, limits)
+	for _, f := range r.File {
+		fpath, err := guard.Entry(f.Name, f.Mode())
+		if err != nil {
+			return filenames, err
+		}
+		if f.FileInfo().IsDir() {
+			if err = os.MkdirAll(fpath, os.ModePerm); err != nil {
+				return filenames, NewError(ErrStorage, "解压文件失败", err)
+			}
+			continue
+		}
+		if err = unzipFile(guard, f, fpath); err != nil {
+			return filenames, err
+		}
+	}
+	return filenames, nil
+}
+
+func unzipFile(guard *ExtractGuard, f *zip.File, fpath string) error {
+	if err := os.MkdirAll(filepath.Dir(fpath), os.ModePerm); err != nil {
+		return NewError(ErrStorage, "解压文件失败", err)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return NewError(ErrInvalidParam, "压缩文件已损坏", err)
+	}
+	defer rc.Close()
+	outFile, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode().Perm()|0600)
+	if err != nil {
+		return NewError(ErrStorage, "解压文件失败", err)
+	}
+	_, err = guard.Copy(outFile, rc, int64(f.CompressedSize64))
+	if cerr := outFile.Close(); err == nil && cerr != nil {
+		return NewError(ErrStorage, "解压文件失败", cerr)
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	if err != nil {
+		return NewError(ErrInvalidParam, "压缩文件已损坏", err)
+	}
+	return nil
+}
+
+/*
+	压缩包创建
+*/
+
+// ZipStore 压缩级别取该值时只存储不压缩
+const ZipStore = -1
+
+// ZipOptions 打包选项
+type ZipOptions struct {
+	Include []string // 只打包匹配的文件，按相对路径或文件名匹配glob，为空时打包全部文件
+	Exclude []string // 排除匹配的文件或目录，按相对路径或文件名匹配glob
+	Level   int      // 压缩级别1-9，0使用默认级别，ZipStore只存储
+}
+
+// matchAny 相对路径或文件名匹配任一glob即返回true
+func matchAny(patterns []string, rel string) bool {
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, rel); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(p, filepath.Base(rel)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// 文件目录压缩
+func AddDirToZip(zipFileName string, dir string, pathInZip string) error {
+	return ZipDir(zipFileName, dir, pathInZip, ZipOptions{})
+}
+
+// ZipDir 将目录或单个文件打包为zip文件，pathInZip为其在压缩包中的路径
+func ZipDir(zipFileName string, dir string, pathInZip string, opt ZipOptions) error {
+	zipFile, err := os.Create(zipFileName)
+	if err != nil {
+		return err
+	}
+	writer := NewZipWriter(zipFile, opt.Level)
+	err = addToZip(writer, make(map[string]bool), zipFileName, dir, pathInZip, opt)
+	if cerr := writer.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := zipFile.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(zipFileName)
+	}
+	return err
+}
+
+// NewZipWriter 创建指定压缩级别的 zip.Writer
+func NewZipWriter(w io.Writer, level int) *zip.Writer {
+	writer := zip.NewWriter(w)
+	if level != 0 && level != ZipStore {
+		writer.RegisterCompressor(zip.Deflate, func(out io.Writer) (io.WriteCloser, error) {
+			return flate.NewWriter(out, level)
+		})
+	}
+	return writer
+}
+
+// addToZip 遍历一次目录树写入同一个 zip.Writer，written记录已写入的目录条目，打包时跳过正在写入的zipFileName
+func addToZip(writer *zip.Writer, written map[string]bool, zipFileName, dir, pathInZip string, opt ZipOptions) error {
+	var skip string
+	if zipFileName != "" {
+		skip, _ = filepath.Abs(zipFileName)
+	}
+	return WalkFiles(dir, pathInZip, opt, written, func(name, fpath string, info fs.FileInfo) error {
+		if abs, _ := filepath.Abs(fpath); skip != "" && abs == skip {
+			return nil
+		}
+		return addFileToZip(writer, fpath, name, info, opt.Level)
+	})
+}
+
+// WalkFiles 遍历一次目录树，按打包选项过滤后对每个目录和普通文件调用fn，
+// name为条目在压缩包中的路径，目录不带结尾的斜杠；written记录已处理的目录，可为nil
+func WalkFiles(dir, pathInZip string, opt ZipOptions, written map[string]bool, fn func(name, fpath string, info fs.FileInfo) error) error {
+	if written == nil {
+		written = make(map[string]bool)
+	}
+	// addParents 只打包部分文件时按需补齐上级目录条目
+	var addParents func(name, fpath string) error
+	addParents = func(name, fpath string) error {
+		parent, parentPath := path.Dir(name), filepath.Dir(fpath)
+		if parent == "." || parent == "/" || written[parent] {
+			return nil
+		}
+		if err := addParents(parent, parentPath); err != nil {
+			return err
+		}
+		written[parent] = true
+		info, err := os.Stat(parentPath)
+		if err != nil {
+			return err
+		}
+		return fn(parent, parentPath, info)
+	}
+	return filepath.WalkDir(dir, func(fpath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, fpath)
+		if err != nil {
+			return err
+		}
+		if rel != "." && matchAny(opt.Exclude, filepath.ToSlash(rel)) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// 不打包符号链接等特殊文件，与解压时的限制保持一致
+		if !d.IsDir() && !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		name := path.Join(filepath.ToSlash(pathInZip), filepath.ToSlash(rel))
+		if rel == "." {
+			name = path.Clean(filepath.ToSlash(pathInZip))
+		}
+		if d.IsDir() {
+			if len(opt.Include) > 0 || name == "." || name == "" || written[name] {
+				return nil
+			}
+			written[name] = true
+			return fn(name, fpath, info)
+		}
+		if len(opt.Include) > 0 && !matchAny(opt.Include, filepath.ToSlash(rel)) {
+			return nil
+		}
+		if err = addParents(name, fpath); err != nil {
+			return err
+		}
+		return fn(name, fpath, info)
+	})
+}
+
+// addFileToZip 写入单个文件或目录条目，保留修改时间和权限
+func addFileToZip(writer *zip.Writer, fpath, name string, info fs.FileInfo, level int) error {
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if info.IsDir() {
+		header.Name += "/"
+		_, err = writer.CreateHeader(header)
+		return err
+	}
+	header.Method = zip.Deflate
+	if level == ZipStore {
+		header.Method = zip.Store
+	}
+	w, err := writer.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(fpath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// ZipBuilder 向任意 io.Writer 流式写入zip，条目可来自内存，不经过文件系统
+type ZipBuilder struct {
+	writer *zip.Writer
+	level  int
+	dirs   map[string]bool
+}
+
+// NewZipBuilder 创建zip构建器，level取值同 ZipOptions.Level
+func NewZipBuilder(w io.Writer, level int) *ZipBuilder {
+	return &ZipBuilder{writer: NewZipWriter(w, level), level: level, dirs: make(map[string]bool)}
+}
+
+// cleanZipName 条目名称统一为相对路径，拒绝跳出压缩包根目录的名称
+func cleanZipName(name string) (string, error) {
+	clean := path.Clean(strings.ReplaceAll(name, "\\", "/"))
+	if clean == "." || clean == ".." || strings.HasPrefix(clean, "../") || path.IsAbs(clean) {
+		return "", NewError(ErrInvalidParam, "非法的文件名", fmt.Errorf("%s: illegal file path", name))
+	}
+	return clean, nil
+}
+
+// addParents 补齐条目的上级目录
+func (b *ZipBuilder) addParents(name string) error {
+	parent := path.Dir(name)
+	if parent == "." || b.dirs[parent] {
+		return nil
+	}
+	if err := b.addParents(parent); err != nil {
+		return err
+	}
+	b.dirs[parent] = true
+	header := &zip.FileHeader{Name: parent + "/", Method: zip.Store, Modified: time.Now()}
+	header.SetMode(fs.ModeDir | 0755)
+	_, err := b.writer.CreateHeader(header)
+	return err
+}
+
+// AddReader 从r读取内容写入名为name的条目
+func (b *ZipBuilder) AddReader(name string, r io.Reader, modTime time.Time) error {
+	name, err := cleanZipName(name)
+	if err != nil {
+		return err
+	}
+	if err = b.addParents(name); err != nil {
+		return err
+	}
+	header := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modTime}
+	if b.level == ZipStore {
+		header.Method = zip.Store
+	}
+	header.SetMode(0644)
+	w, err := b.writer.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
+
+// AddBytes 写入内存中的文件
+func (b *ZipBuilder) AddBytes(name string, data []byte) error {
+	return b.AddReader(name, bytes.NewReader(data), time.Now())
+}
+
+// AddString 写入文本文件
+func (b *ZipBuilder) AddString(name, content string) error {
+	return b.AddReader(name, strings.NewReader(content), time.Now())
+}
+
+// AddDir 将磁盘上的目录写入压缩包
+func (b *ZipBuilder) AddDir(dir, pathInZip string, opt ZipOptions) error {
+	opt.Level = b.level
+	return addToZip(b.writer, b.dirs, "", dir, pathInZip, opt)
+}
+
+// Close 写入zip目录，不关闭底层的 io.Writer
+func (b *ZipBuilder) Close() error {
+	return b.writer.Close()
+}
+
+// WriteZip 将内存中的文件打包写入w，files的键为文件名，按名称排序保证输出稳定
+func WriteZip(w io.Writer, files map[string]string, level int) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	builder := NewZipBuilder(w, level)
+	for _, name := range names {
+		if err := builder.AddString(name, files[name]); err != nil {
+			return err
+		}
+	}
+	return builder.Close()
+}
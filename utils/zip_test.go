@@ -0,0 +1,231 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// buildZip 按顺序写入条目，名称以/结尾的为目录
+func buildZip(t *testing.T, entries map[string]string, order ...string) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, name := range order {
+		header := &zip.FileHeader{Name: name, Method: zip.Deflate}
+		if strings.HasSuffix(name, "/") {
+			header.SetMode(fs.ModeDir | 0755)
+		} else {
+			header.SetMode(0644)
+		}
+		fw, err := w.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write([]byte(entries[name]))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestUnzipReader(t *testing.T) {
+	dest := t.TempDir()
+	r := buildZip(t, map[string]string{"a/b.txt": "hello"}, "./", "a/", "a/b.txt")
+	first, err := UnzipReader(r, r.Size(), dest, DefaultExtractLimits)
+	if err != nil {
+		t.Fatalf("unzip: %v", err)
+	}
+	if first != "./" {
+		t.Errorf("first = %q", first)
+	}
+	data, err := os.ReadFile(filepath.Join(dest, "a", "b.txt"))
+	if err != nil || string(data) != "hello" {
+		t.Errorf("content = %q, %v", data, err)
+	}
+}
+
+func TestUnzipReaderRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		limits  ExtractLimits
+		kind    error
+	}{
+		{"zip slip", []string{"../evil.txt"}, DefaultExtractLimits, ErrInvalidParam},
+		{"nested zip slip", []string{"a/../../evil.txt"}, DefaultExtractLimits, ErrInvalidParam},
+		{"root as file", []string{"."}, DefaultExtractLimits, ErrInvalidParam},
+		{"too many entries", []string{"a.txt", "b.txt"}, ExtractLimits{MaxEntries: 1}, ErrTooLarge},
+		{"too deep", []string{"a/b/c.txt"}, ExtractLimits{MaxDepth: 2}, ErrTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest := t.TempDir()
+			r := buildZip(t, map[string]string{}, tt.entries...)
+			_, err := UnzipReader(r, r.Size(), dest, tt.limits)
+			if ErrorKind(err) != tt.kind {
+				t.Fatalf("err = %v, want kind %v", err, tt.kind)
+			}
+			if _, err := os.Stat(filepath.Join(filepath.Dir(dest), "evil.txt")); err == nil {
+				t.Fatal("file written outside dest")
+			}
+		})
+	}
+}
+
+func TestUnzipReaderBomb(t *testing.T) {
+	content := strings.Repeat("0", 4<<20)
+	r := buildZip(t, map[string]string{"bomb.txt": content}, "bomb.txt")
+	_, err := UnzipReader(r, r.Size(), t.TempDir(), ExtractLimits{MaxRatio: 10})
+	if ErrorKind(err) != ErrTooLarge {
+		t.Fatalf("err = %v, want too large", err)
+	}
+	_, err = UnzipReader(r, r.Size(), t.TempDir(), ExtractLimits{MaxFileSize: 1 << 20})
+	if ErrorKind(err) != ErrTooLarge {
+		t.Fatalf("err = %v, want too large", err)
+	}
+}
+
+func TestExtractGuardEntry(t *testing.T) {
+	dest := t.TempDir()
+	g := NewExtractGuard(dest, DefaultExtractLimits)
+	if p, err := g.Entry("./", fs.ModeDir|0755); err != nil || p != dest {
+		t.Errorf("root dir: %q, %v", p, err)
+	}
+	if _, err := g.Entry("link", fs.ModeSymlink|0777); ErrorKind(err) != ErrInvalidParam {
+		t.Errorf("symlink: %v", err)
+	}
+	if _, err := g.Entry("fifo", fs.ModeNamedPipe|0644); ErrorKind(err) != ErrInvalidParam {
+		t.Errorf("named pipe: %v", err)
+	}
+}
+
+type failWriter struct{}
+
+func (failWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("no space left on device")
+}
+
+func TestExtractGuardCopyWriteError(t *testing.T) {
+	g := NewExtractGuard(t.TempDir(), DefaultExtractLimits)
+	_, err := g.Copy(failWriter{}, strings.NewReader("data"), 0)
+	if ErrorKind(err) != ErrStorage {
+		t.Fatalf("err = %v, want storage error", err)
+	}
+}
+
+// zipTree 创建两层以上的目录树，返回根目录
+func zipTree(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.txt":          "a",
+		"run.sh":         "#!/bin/sh",
+		"skip/e.txt":     "e",
+		"sub/b.log":      "b",
+		"sub/deep/c.txt": "c",
+		"sub/deep/d.tmp": "d",
+	}
+	for name, content := range files {
+		fpath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fpath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fpath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chmod(filepath.Join(dir, "run.sh"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestZipDir(t *testing.T) {
+	tests := []struct {
+		name      string
+		pathInZip string
+		opt       ZipOptions
+		want      []string
+	}{
+		{"all", "pkg", ZipOptions{}, []string{"pkg/", "pkg/a.txt", "pkg/run.sh", "pkg/skip/", "pkg/skip/e.txt", "pkg/sub/", "pkg/sub/b.log", "pkg/sub/deep/", "pkg/sub/deep/c.txt", "pkg/sub/deep/d.tmp"}},
+		{"no root", "", ZipOptions{Level: ZipStore}, []string{"a.txt", "run.sh", "skip/", "skip/e.txt", "sub/", "sub/b.log", "sub/deep/", "sub/deep/c.txt", "sub/deep/d.tmp"}},
+		{"exclude", "pkg", ZipOptions{Exclude: []string{"skip", "*.tmp"}}, []string{"pkg/", "pkg/a.txt", "pkg/run.sh", "pkg/sub/", "pkg/sub/b.log", "pkg/sub/deep/", "pkg/sub/deep/c.txt"}},
+		{"include", "pkg", ZipOptions{Include: []string{"*.txt"}, Exclude: []string{"skip"}}, []string{"pkg/", "pkg/a.txt", "pkg/sub/", "pkg/sub/deep/", "pkg/sub/deep/c.txt"}},
+		{"include by path", "", ZipOptions{Include: []string{"sub/*/c.txt"}}, []string{"sub/", "sub/deep/", "sub/deep/c.txt"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := zipTree(t)
+			mtime := time.Date(2024, 5, 6, 7, 8, 10, 0, time.UTC)
+			for _, name := range []string{"a.txt", "sub/deep/c.txt"} {
+				if err := os.Chtimes(filepath.Join(dir, filepath.FromSlash(name)), mtime, mtime); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// 压缩包写在被打包的目录中，不能把自己打包进去
+			zipName := filepath.Join(dir, "out.zip")
+			if err := ZipDir(zipName, dir, tt.pathInZip, tt.opt); err != nil {
+				t.Fatal(err)
+			}
+			zr, err := zip.OpenReader(zipName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer zr.Close()
+			var names []string
+			for _, f := range zr.File {
+				names = append(names, f.Name)
+				rel := strings.TrimPrefix(strings.TrimPrefix(f.Name, tt.pathInZip), "/")
+				fpath := filepath.Join(dir, filepath.FromSlash(rel))
+				info, err := os.Stat(fpath)
+				if err != nil {
+					t.Fatalf("%s: %v", f.Name, err)
+				}
+				if f.Mode() != info.Mode() {
+					t.Errorf("%s: mode = %v, want %v", f.Name, f.Mode(), info.Mode())
+				}
+				if !f.Modified.Equal(info.ModTime().Truncate(time.Second)) {
+					t.Errorf("%s: modified = %v, want %v", f.Name, f.Modified, info.ModTime())
+				}
+				if f.FileInfo().IsDir() {
+					continue
+				}
+				rc, err := f.Open()
+				if err != nil {
+					t.Fatal(err)
+				}
+				data, err := io.ReadAll(rc)
+				rc.Close()
+				want, _ := os.ReadFile(fpath)
+				if err != nil || !bytes.Equal(data, want) {
+					t.Errorf("%s: content = %q, %v", f.Name, data, err)
+				}
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Fatalf("entries = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestWalkFilesSingleFile(t *testing.T) {
+	dir := zipTree(t)
+	var names []string
+	err := WalkFiles(filepath.Join(dir, "sub", "deep", "c.txt"), "x/c.txt", ZipOptions{}, nil, func(name, fpath string, info fs.FileInfo) error {
+		names = append(names, name)
+		return nil
+	})
+	if err != nil || !reflect.DeepEqual(names, []string{"x", "x/c.txt"}) {
+		t.Fatalf("names = %v, %v", names, err)
+	}
+}
@@ -0,0 +1,143 @@
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Required 不能为零值，字符串去掉首尾空白后不能为空
+func Required() Rule {
+	return RuleFunc(func(v reflect.Value) string {
+		if v.Kind() == reflect.String && strings.TrimSpace(v.String()) == "" || v.IsZero() {
+			return "不能为空"
+		}
+		return ""
+	})
+}
+
+// ReadOnly 由服务端维护的字段，客户端不能设置
+func ReadOnly() Rule {
+	return RuleFunc(func(v reflect.Value) string {
+		if !v.IsZero() {
+			return "只读字段，不能由客户端设置"
+		}
+		return ""
+	})
+}
+
+// MaxLen 字符串最多n个字符，切片最多n个元素
+func MaxLen(n int) Rule {
+	return RuleFunc(func(v reflect.Value) string {
+		if length(v) > n {
+			return fmt.Sprintf("长度不能超过%d", n)
+		}
+		return ""
+	})
+}
+
+// MinLen 字符串至少n个字符，切片至少n个元素，为空时不校验，需要时配合 Required 使用
+func MinLen(n int) Rule {
+	return RuleFunc(func(v reflect.Value) string {
+		if l := length(v); l > 0 && l < n {
+			return fmt.Sprintf("长度不能少于%d", n)
+		}
+		return ""
+	})
+}
+
+func length(v reflect.Value) int {
+	if v.Kind() == reflect.String {
+		return utf8.RuneCountInString(v.String())
+	}
+	return v.Len()
+}
+
+// Range 整数在[min, max]范围内
+func Range(min, max int64) Rule {
+	return RuleFunc(func(v reflect.Value) string {
+		if n, ok := intValue(v); !ok || n < min || n > max {
+			return fmt.Sprintf("取值范围为%d到%d", min, max)
+		}
+		return ""
+	})
+}
+
+// OneOf 整数只能取指定的值
+func OneOf(values ...int64) Rule {
+	allowed := make([]string, len(values))
+	for i, n := range values {
+		allowed[i] = strconv.FormatInt(n, 10)
+	}
+	desc := "取值必须为 " + strings.Join(allowed, "、") + " 之一"
+	return RuleFunc(func(v reflect.Value) string {
+		n, ok := intValue(v)
+		for _, x := range values {
+			if ok && n == x {
+				return ""
+			}
+		}
+		return desc
+	})
+}
+
+// Enum 枚举值必须已定义，names为生成代码中的 XXX_name，切片时校验每个元素
+func Enum(names map[int32]string) Rule {
+	values := make([]int, 0, len(names))
+	for n := range names {
+		values = append(values, int(n))
+	}
+	sort.Ints(values)
+	allowed := make([]string, len(values))
+	for i, n := range values {
+		allowed[i] = fmt.Sprintf("%d(%s)", n, names[int32(n)])
+	}
+	desc := "取值必须为 " + strings.Join(allowed, "、") + " 之一"
+	valid := func(v reflect.Value) bool {
+		n, ok := intValue(v)
+		_, defined := names[int32(n)]
+		return ok && n == int64(int32(n)) && defined
+	}
+	return RuleFunc(func(v reflect.Value) string {
+		if v.Kind() == reflect.Slice {
+			for i := 0; i < v.Len(); i++ {
+				if !valid(v.Index(i)) {
+					return desc
+				}
+			}
+			return ""
+		}
+		if !valid(v) {
+			return desc
+		}
+		return ""
+	})
+}
+
+// ObjectId 非空时必须为24位十六进制的ObjectId
+func ObjectId() Rule {
+	return RuleFunc(func(v reflect.Value) string {
+		if s := v.String(); s != "" && !primitive.IsValidObjectID(s) {
+			return "格式错误，应为24位十六进制ID"
+		}
+		return ""
+	})
+}
+
+// intValue 取出整数字段的值，超出int64的无符号数视为不合法
+func intValue(v reflect.Value) (int64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if u := v.Uint(); u <= 1<<63-1 {
+			return int64(u), true
+		}
+	}
+	return 0, false
+}
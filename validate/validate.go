@@ -0,0 +1,176 @@
+package validate
+
+import (
+	"fmt"
+	"playGround/utils"
+	"reflect"
+	"strings"
+	"sync"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Rule 字段的校验规则，不通过时返回错误描述，通过时返回空字符串
+type Rule interface {
+	Check(v reflect.Value) string
+}
+
+// RuleFunc 函数形式的 Rule
+type RuleFunc func(v reflect.Value) string
+
+func (f RuleFunc) Check(v reflect.Value) string {
+	return f(v)
+}
+
+// FieldRules 一个字段的规则，Name为字段的JSON名称
+type FieldRules struct {
+	Name  string
+	Rules []Rule
+}
+
+// Field 声明字段的规则
+func Field(name string, rules ...Rule) FieldRules {
+	return FieldRules{Name: name, Rules: rules}
+}
+
+// Violation 字段的校验错误
+type Violation = utils.Violation
+
+// ruleSet 一个场景的规则，字段按声明顺序校验
+type ruleSet struct {
+	typ    reflect.Type
+	fields []FieldRules
+	index  [][]int
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]*ruleSet{}
+)
+
+// Register 注册一个场景的规则，msg为消息的指针，字段名不存在或重复注册时panic
+func Register(scene string, msg interface{}, fields ...FieldRules) {
+	typ := reflect.TypeOf(msg)
+	if typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validate: %s: msg must be a pointer to struct, got %s", scene, typ))
+	}
+	typ = typ.Elem()
+	rs := &ruleSet{typ: typ, fields: fields}
+	for _, f := range fields {
+		idx, ok := fieldIndex(typ, f.Name)
+		if !ok {
+			panic(fmt.Sprintf("validate: %s: unknown field %q in %s", scene, f.Name, typ))
+		}
+		rs.index = append(rs.index, idx)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := registry[scene]; ok {
+		panic(fmt.Sprintf("validate: scene %s registered twice", scene))
+	}
+	registry[scene] = rs
+}
+
+// fieldIndex 按JSON名称查找字段，没有json标签时使用字段名
+func fieldIndex(typ reflect.Type, name string) ([]int, bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := strings.Split(f.Tag.Get("json"), ",")[0]
+		if tag == name || tag == "" && f.Name == name {
+			return f.Index, true
+		}
+	}
+	return nil, false
+}
+
+// Violations 按场景的规则校验消息，返回所有不通过的字段，fields不为空时只校验这些字段
+func Violations(scene string, msg interface{}, fields ...string) ([]Violation, error) {
+	mu.RLock()
+	rs, ok := registry[scene]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("validate: scene %s not registered", scene)
+	}
+	v := reflect.ValueOf(msg)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return []Violation{{Field: "", Description: "请求不能为空"}}, nil
+		}
+		v = v.Elem()
+	}
+	if v.Type() != rs.typ {
+		return nil, fmt.Errorf("validate: scene %s expects %s, got %s", scene, rs.typ, v.Type())
+	}
+	var out []Violation
+	for i, f := range rs.fields {
+		if len(fields) > 0 && !contains(fields, f.Name) {
+			continue
+		}
+		fv := v.FieldByIndex(rs.index[i])
+		for _, r := range f.Rules {
+			if desc := r.Check(fv); desc != "" {
+				out = append(out, Violation{Field: f.Name, Description: desc})
+				// 同一字段只报告第一条不通过的规则
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+// Check 按场景的规则校验消息，不通过时返回带 BadRequest 详情的 InvalidArgument 错误，fields不为空时只校验这些字段
+func Check(scene string, msg interface{}, fields ...string) error {
+	vs, err := Violations(scene, msg, fields...)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if len(vs) == 0 {
+		return nil
+	}
+	return Error(vs)
+}
+
+// Mask 校验部分更新的字段列表，只能包含allowed中的字段，field为字段列表本身的名称
+func Mask(field string, paths []string, allowed ...string) []Violation {
+	if len(paths) == 0 {
+		return []Violation{{Field: field, Description: "没有要修改的字段"}}
+	}
+	var out []Violation
+	for _, p := range paths {
+		if !contains(allowed, p) {
+			out = append(out, Violation{Field: field, Description: "不支持修改的字段: " + p})
+		}
+	}
+	return out
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Error 将字段错误转换为gRPC错误，消息为第一条错误
+func Error(vs []Violation) error {
+	msg := "参数校验失败"
+	if len(vs) > 0 {
+		msg += ": " + strings.TrimSpace(vs[0].Field+" "+vs[0].Description)
+	}
+	br := &errdetails.BadRequest{}
+	for _, v := range vs {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{Field: v.Field, Description: v.Description})
+	}
+	st, err := status.New(codes.InvalidArgument, msg).WithDetails(br)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, msg)
+	}
+	return st.Err()
+}
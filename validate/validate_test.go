@@ -0,0 +1,132 @@
+package validate
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type testMsg struct {
+	Id      string  `json:"id"`
+	Title   string  `json:"title"`
+	Type    int32   `json:"type"`
+	Types   []int32 `json:"types"`
+	Page    int64   `json:"page"`
+	Created int64   `json:"created_at"`
+	Raw     string
+}
+
+var testTypes = map[int32]string{0: "NEWS", 1: "AWARD"}
+
+func init() {
+	Register("test", &testMsg{},
+		Field("id", ObjectId()),
+		Field("title", Required(), MinLen(2), MaxLen(4)),
+		Field("type", Enum(testTypes)),
+		Field("types", MaxLen(2), Enum(testTypes)),
+		Field("page", Range(1, 100)),
+		Field("created_at", ReadOnly()),
+		Field("Raw", MaxLen(3)),
+	)
+}
+
+func TestViolations(t *testing.T) {
+	tests := []struct {
+		name   string
+		msg    testMsg
+		fields []string
+		want   []string
+	}{
+		{"valid", testMsg{Title: "标题", Page: 1}, nil, nil},
+		{"blank title", testMsg{Title: "  ", Page: 1}, nil, []string{"title"}},
+		{"title counted in runes", testMsg{Title: "四个汉字", Page: 1}, nil, nil},
+		{"title too long", testMsg{Title: "五个汉字啊", Page: 1}, nil, []string{"title"}},
+		{"title too short", testMsg{Title: "a", Page: 1}, nil, []string{"title"}},
+		{"bad id", testMsg{Id: "123", Title: "ab", Page: 1}, nil, []string{"id"}},
+		{"undefined enum", testMsg{Title: "ab", Type: 5, Page: 1}, nil, []string{"type"}},
+		{"undefined enum in slice", testMsg{Title: "ab", Types: []int32{0, 7}, Page: 1}, nil, []string{"types"}},
+		{"too many types", testMsg{Title: "ab", Types: []int32{0, 1, 0}, Page: 1}, nil, []string{"types"}},
+		{"page out of range", testMsg{Title: "ab"}, nil, []string{"page"}},
+		{"read only", testMsg{Title: "ab", Page: 1, Created: 1}, nil, []string{"created_at"}},
+		{"field without json tag", testMsg{Title: "ab", Page: 1, Raw: "long"}, nil, []string{"Raw"}},
+		{"all errors reported", testMsg{Id: "x", Type: 9}, nil, []string{"id", "title", "type", "page"}},
+		{"only listed fields", testMsg{Id: "x", Type: 9}, []string{"type"}, []string{"type"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vs, err := Violations("test", &tt.msg, tt.fields...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, v := range vs {
+				got = append(got, v.Field)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("violations = %v, want %v", vs, tt.want)
+			}
+		})
+	}
+}
+
+func TestViolationsMisuse(t *testing.T) {
+	if _, err := Violations("missing", &testMsg{}); err == nil {
+		t.Error("unregistered scene accepted")
+	}
+	if _, err := Violations("test", &struct{}{}); err == nil {
+		t.Error("wrong message type accepted")
+	}
+	if vs, err := Violations("test", (*testMsg)(nil)); err != nil || len(vs) != 1 {
+		t.Errorf("nil message = %v, %v", vs, err)
+	}
+	for name, fn := range map[string]func(){
+		"unknown field": func() { Register("bad", &testMsg{}, Field("nope")) },
+		"not a pointer": func() { Register("bad", testMsg{}) },
+		"twice":         func() { Register("test", &testMsg{}) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: no panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if err := Check("test", &testMsg{Title: "ab", Page: 1}); err != nil {
+		t.Fatal(err)
+	}
+	err := Check("test", &testMsg{Page: 1, Type: 3})
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument || st.Message() != "参数校验失败: title 不能为空" {
+		t.Fatalf("status = %v", st)
+	}
+	if len(st.Details()) != 1 {
+		t.Fatalf("details = %v", st.Details())
+	}
+	br, ok := st.Details()[0].(*errdetails.BadRequest)
+	if !ok || len(br.FieldViolations) != 2 || br.FieldViolations[1].Field != "type" {
+		t.Fatalf("bad request = %v", st.Details()[0])
+	}
+	if status.Code(Check("missing", &testMsg{})) != codes.Internal {
+		t.Fatal("unregistered scene should be internal error")
+	}
+}
+func TestMask(t *testing.T) {
+	if vs := Mask("update_mask", nil, "title"); len(vs) != 1 {
+		t.Errorf("empty mask = %v", vs)
+	}
+	if vs := Mask("update_mask", []string{"title", "content"}, "title", "content"); len(vs) != 0 {
+		t.Errorf("allowed mask = %v", vs)
+	}
+	vs := Mask("update_mask", []string{"title", "version", "id"}, "title")
+	if len(vs) != 2 || vs[0].Field != "update_mask" || vs[0].Description != "不支持修改的字段: version" {
+		t.Errorf("disallowed mask = %v", vs)
+	}
+}